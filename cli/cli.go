@@ -0,0 +1,126 @@
+// Package cli is a skeleton for command-line binaries with subcommands, configuration loaded by
+// the config package, a version command and signal-aware contexts.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/denis-kilchichakov/toolbox/config"
+	"github.com/denis-kilchichakov/toolbox/system"
+)
+
+type Command struct {
+	Name  string
+	Usage string
+	// Config is an optional pointer to a config struct, loaded with config.LoadArgs from the
+	// command's arguments, the environment and App.ConfigFile before Run is called.
+	Config any
+	// Run gets a context canceled on SIGINT/SIGTERM and positional arguments left after flags.
+	Run func(ctx context.Context, args []string) error
+}
+
+type App struct {
+	Name     string
+	Commands []*Command
+	// EnvPrefix is prepended to `env` tags of command configs.
+	EnvPrefix string
+	// ConfigFile is an optional YAML or JSON file applied to command configs.
+	ConfigFile string
+	// Output receives usage and version output, defaults to os.Stdout.
+	Output io.Writer
+}
+
+// Main runs the command named by os.Args[1] through system.Main, exiting with a non-zero code
+// on failure.
+func (a *App) Main() {
+	system.Main(func(ctx context.Context) error {
+		return a.Run(ctx, os.Args[1:])
+	})
+}
+
+// Run dispatches args to a command. "help" and "version" commands are built in.
+func (a *App) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		a.usage()
+		return errors.New("no command given")
+	}
+
+	name, args := args[0], args[1:]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		a.usage()
+		return nil
+	case "version", "-version", "--version":
+		a.version()
+		return nil
+	}
+
+	cmd := a.command(name)
+	if cmd == nil {
+		a.usage()
+		return fmt.Errorf("unknown command: %s", name)
+	}
+
+	if cmd.Config != nil {
+		var err error
+		args, err = config.LoadArgs(cmd.Config, config.Options{
+			File:      a.ConfigFile,
+			EnvPrefix: a.EnvPrefix,
+			Args:      args,
+			Name:      a.Name + " " + cmd.Name,
+		})
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return cmd.Run(ctx, args)
+}
+
+func (a *App) command(name string) *Command {
+	for _, cmd := range a.Commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (a *App) output() io.Writer {
+	if a.Output == nil {
+		return os.Stdout
+	}
+	return a.Output
+}
+
+func (a *App) usage() {
+	w := tabwriter.NewWriter(a.output(), 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [args]\n\nCommands:\n", a.Name)
+
+	commands := append([]*Command(nil), a.Commands...)
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", cmd.Name, cmd.Usage)
+	}
+	fmt.Fprintf(w, "  %s\t%s\n", "version", "Print version information")
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for command flags.\n", a.Name)
+	w.Flush()
+}
+
+func (a *App) version() {
+	info := system.GetBuildInfo()
+	fmt.Fprintf(a.output(), "%s %s", a.Name, info.Version)
+	if info.Commit != "" {
+		fmt.Fprintf(a.output(), " (%s)", info.Commit)
+	}
+	fmt.Fprintln(a.output())
+}
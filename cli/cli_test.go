@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type serveConfig struct {
+	Port  int  `env:"PORT" flag:"port" default:"8080"`
+	Debug bool `flag:"debug"`
+}
+
+func testApp(out *bytes.Buffer) (*App, *serveConfig, *[]string) {
+	cfg := &serveConfig{}
+	var gotArgs []string
+	app := &App{
+		Name:      "bot",
+		EnvPrefix: "BOT_",
+		Output:    out,
+		Commands: []*Command{
+			{
+				Name:   "serve",
+				Usage:  "Run the bot",
+				Config: cfg,
+				Run: func(ctx context.Context, args []string) error {
+					gotArgs = args
+					return nil
+				},
+			},
+			{
+				Name:  "migrate",
+				Usage: "Apply migrations",
+				Run: func(ctx context.Context, args []string) error {
+					gotArgs = args
+					return nil
+				},
+			},
+		},
+	}
+	return app, cfg, &gotArgs
+}
+
+func TestApp_RunCommand(t *testing.T) {
+	// given
+	t.Setenv("BOT_PORT", "9000")
+	app, cfg, args := testApp(&bytes.Buffer{})
+
+	// when
+	err := app.Run(context.Background(), []string{"serve", "-debug", "extra"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, serveConfig{Port: 9000, Debug: true}, *cfg)
+	assert.Equal(t, []string{"extra"}, *args)
+}
+
+func TestApp_RunCommandWithoutConfig(t *testing.T) {
+	// given
+	app, _, args := testApp(&bytes.Buffer{})
+
+	// when
+	err := app.Run(context.Background(), []string{"migrate", "-down", "1"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-down", "1"}, *args)
+}
+
+func TestApp_Usage(t *testing.T) {
+	// given
+	var out bytes.Buffer
+	app, _, _ := testApp(&out)
+
+	// when
+	err := app.Run(context.Background(), []string{"help"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, `Usage: bot <command> [flags] [args]
+
+Commands:
+  migrate  Apply migrations
+  serve    Run the bot
+  version  Print version information
+
+Run 'bot <command> -h' for command flags.
+`, out.String())
+}
+
+func TestApp_Version(t *testing.T) {
+	// given
+	var out bytes.Buffer
+	app, _, _ := testApp(&out)
+
+	// when
+	err := app.Run(context.Background(), []string{"version"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "bot dev")
+}
+
+func TestApp_Errors(t *testing.T) {
+	app, _, _ := testApp(&bytes.Buffer{})
+
+	assert.EqualError(t, app.Run(context.Background(), nil), "no command given")
+	assert.EqualError(t, app.Run(context.Background(), []string{"deploy"}), "unknown command: deploy")
+	assert.Error(t, app.Run(context.Background(), []string{"serve", "-port", "http"}))
+}
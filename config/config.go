@@ -0,0 +1,287 @@
+// Package config loads configuration into tagged structs.
+//
+// Values are applied in increasing order of precedence: `default` tags, a YAML or JSON file,
+// environment variables named by `env` tags and command-line flags named by `flag` tags:
+//
+//	type Config struct {
+//		DBPath   string        `yaml:"db_path" env:"DB_PATH" flag:"db" default:"bot.db" usage:"database file"`
+//		Token    string        `yaml:"token" env:"BOT_TOKEN" validate:"required"`
+//		Timeout  time.Duration `yaml:"timeout" env:"TIMEOUT" default:"30s"`
+//		AdminIDs []int64       `yaml:"admin_ids" env:"ADMIN_IDS"`
+//	}
+//
+// Supported field types are strings, booleans, integers, floats, time.Duration and slices of
+// those (comma-separated in env vars, flags and defaults). Nested structs are traversed.
+//
+// After loading, fields are checked against `validate` tags, see package validation. The older
+// `required:"true"` tag is deprecated and checked like `validate:"required"`.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/validation"
+	"gopkg.in/yaml.v3"
+)
+
+type Options struct {
+	// File is an optional YAML (.yaml, .yml) or JSON (.json) file, both decoded using `yaml` tags.
+	// A missing file is an error.
+	File string
+	// EnvPrefix is prepended to every `env` tag, e.g. "BOT_".
+	EnvPrefix string
+	// Args are command-line arguments without the program name, usually os.Args[1:].
+	// Flags are not parsed when Args is nil.
+	Args []string
+	// Name is shown in flag usage messages, defaults to the program name.
+	Name string
+}
+
+// Validator is implemented by configs with custom validation, called after all values are applied.
+type Validator interface {
+	Validate() error
+}
+
+type field struct {
+	path  string
+	value reflect.Value
+	tag   reflect.StructTag
+}
+
+// Load fills cfg, which must be a pointer to a struct, according to opts.
+func Load(cfg any, opts Options) error {
+	_, err := LoadArgs(cfg, opts)
+	return err
+}
+
+// LoadArgs is like Load and also returns positional arguments left after flags.
+func LoadArgs(cfg any, opts Options) (args []string, err error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a pointer to a struct, got %T", cfg)
+	}
+	fields := collectFields(v.Elem(), "")
+
+	for _, f := range fields {
+		if def, ok := f.tag.Lookup("default"); ok {
+			if err := setValue(f.value, def); err != nil {
+				return nil, fmt.Errorf("invalid default for %s: %w", f.path, err)
+			}
+		}
+	}
+
+	if opts.File != "" {
+		if err := loadFile(cfg, opts.File); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range fields {
+		name := f.tag.Get("env")
+		if name == "" {
+			continue
+		}
+		if env, ok := os.LookupEnv(opts.EnvPrefix + name); ok {
+			if err := setValue(f.value, env); err != nil {
+				return nil, fmt.Errorf("invalid value of %s%s: %w", opts.EnvPrefix, name, err)
+			}
+		}
+	}
+
+	if opts.Args != nil {
+		args, err = parseFlags(fields, opts.Args, opts.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// required:"true" is an alias of validate:"required", reported with the env var and flag to set
+	var fieldErrs validation.Errors
+	for _, f := range fields {
+		if f.tag.Get("required") == "true" && !hasRequiredRule(f.tag) && f.value.IsZero() {
+			fieldErrs = append(fieldErrs, validation.FieldError{Field: describe(f, opts.EnvPrefix), Rule: "required", Message: "is required"})
+		}
+	}
+	err = validation.Struct(cfg)
+	var validationErrs validation.Errors
+	if errors.As(err, &validationErrs) {
+		fieldErrs = append(fieldErrs, validationErrs...)
+	} else if err != nil {
+		return nil, err
+	}
+	if len(fieldErrs) > 0 {
+		return nil, fieldErrs
+	}
+
+	if validator, ok := cfg.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+func hasRequiredRule(tag reflect.StructTag) bool {
+	for _, rule := range strings.Split(tag.Get("validate"), ",") {
+		if strings.TrimSpace(rule) == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func collectFields(v reflect.Value, prefix string) []field {
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Time{}) {
+			fields = append(fields, collectFields(fv, prefix+sf.Name+".")...)
+			continue
+		}
+		fields = append(fields, field{path: prefix + sf.Name, value: fv, tag: sf.Tag})
+	}
+	return fields
+}
+
+func loadFile(cfg any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		// JSON is valid YAML, decoding it with yaml.v3 keeps `yaml` tags and "30s" durations working
+		err = yaml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file format: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func parseFlags(fields []field, args []string, name string) ([]string, error) {
+	if name == "" {
+		name = filepath.Base(os.Args[0])
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	for _, f := range fields {
+		name := f.tag.Get("flag")
+		if name == "" {
+			continue
+		}
+		fs.Var(&flagValue{f.value}, name, f.tag.Get("usage"))
+	}
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Args(), nil
+}
+
+func describe(f field, envPrefix string) string {
+	var sources []string
+	if env := f.tag.Get("env"); env != "" {
+		sources = append(sources, "env "+envPrefix+env)
+	}
+	if name := f.tag.Get("flag"); name != "" {
+		sources = append(sources, "flag -"+name)
+	}
+	if len(sources) == 0 {
+		return f.path
+	}
+	return fmt.Sprintf("%s (%s)", f.path, strings.Join(sources, ", "))
+}
+
+// flagValue adapts a struct field to flag.Value.
+type flagValue struct {
+	v reflect.Value
+}
+
+func (f *flagValue) String() string {
+	if !f.v.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(f.v.Interface())
+}
+
+func (f *flagValue) Set(s string) error {
+	return setValue(f.v, s)
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.v.IsValid() && f.v.Kind() == reflect.Bool
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if s == "" {
+			v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+			return nil
+		}
+		parts := strings.Split(s, ",")
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
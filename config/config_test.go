@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig struct {
+	DBPath   string        `yaml:"db_path" env:"DB_PATH" flag:"db" default:"bot.db"`
+	Token    string        `yaml:"token" env:"TOKEN" required:"true"`
+	Timeout  time.Duration `yaml:"timeout" env:"TIMEOUT" default:"30s"`
+	AdminIDs []int64       `yaml:"admin_ids" env:"ADMIN_IDS"`
+	Debug    bool          `yaml:"debug" flag:"debug"`
+	HTTP     struct {
+		Port int `yaml:"port" env:"HTTP_PORT" default:"8080"`
+	} `yaml:"http"`
+}
+
+type validatedConfig struct {
+	Workers int `default:"0"`
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.Workers <= 0 {
+		return errors.New("workers must be positive")
+	}
+	return nil
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(content), 0o644)
+	if err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	// given
+	t.Setenv("TEST_TOKEN", "secret")
+	var cfg testConfig
+
+	// when
+	err := Load(&cfg, Options{EnvPrefix: "TEST_"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "bot.db", cfg.DBPath)
+	assert.Equal(t, "secret", cfg.Token)
+	assert.Equal(t, 30*time.Second, cfg.Timeout)
+	assert.Equal(t, 8080, cfg.HTTP.Port)
+	assert.Nil(t, cfg.AdminIDs)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	// given
+	file := writeFile(t, "config.yaml", `
+db_path: file.db
+token: file-token
+timeout: 1m
+admin_ids: [1, 2]
+http:
+  port: 9000
+`)
+	t.Setenv("DB_PATH", "env.db")
+	t.Setenv("ADMIN_IDS", "3, 4")
+	var cfg testConfig
+
+	// when
+	err := Load(&cfg, Options{File: file, Args: []string{"-db", "flag.db", "-debug"}})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "flag.db", cfg.DBPath)
+	assert.Equal(t, "file-token", cfg.Token)
+	assert.Equal(t, time.Minute, cfg.Timeout)
+	assert.Equal(t, []int64{3, 4}, cfg.AdminIDs)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 9000, cfg.HTTP.Port)
+}
+
+func TestLoad_JSON(t *testing.T) {
+	// given
+	file := writeFile(t, "config.json", "{\n\t\"token\": \"json-token\",\n\t\"db_path\": \"json.db\",\n\t\"timeout\": \"1m30s\",\n\t\"http\": {\"port\": 1}\n}")
+	var cfg testConfig
+
+	// when
+	err := Load(&cfg, Options{File: file})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "json-token", cfg.Token)
+	assert.Equal(t, 1, cfg.HTTP.Port)
+	assert.Equal(t, "json.db", cfg.DBPath)
+	assert.Equal(t, 90*time.Second, cfg.Timeout)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	var cfg testConfig
+
+	err := Load(&cfg, Options{})
+	assert.EqualError(t, err, "Token (env TOKEN) is required")
+
+	t.Setenv("TOKEN", "secret")
+	t.Setenv("HTTP_PORT", "http")
+	err = Load(&cfg, Options{})
+	assert.ErrorContains(t, err, "invalid value of HTTP_PORT")
+
+	err = Load(cfg, Options{})
+	assert.ErrorContains(t, err, "must be a pointer to a struct")
+
+	err = Load(&cfg, Options{File: writeFile(t, "config.toml", "")})
+	assert.ErrorContains(t, err, "unsupported config file format")
+
+	err = Load(&cfg, Options{File: filepath.Join(t.TempDir(), "missing.yaml")})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	err = Load(&validatedConfig{}, Options{})
+	assert.EqualError(t, err, "workers must be positive")
+}
+
+func TestLoadArgs(t *testing.T) {
+	// given
+	t.Setenv("TOKEN", "secret")
+	var cfg testConfig
+
+	// when
+	args, err := LoadArgs(&cfg, Options{Args: []string{"-debug", "backup.db", "extra"}})
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, []string{"backup.db", "extra"}, args)
+}
+
+func TestLoad_ValidateTags(t *testing.T) {
+	// given
+	var cfg struct {
+		Workers int    `env:"WORKERS" default:"4" validate:"min=1,max=16"`
+		Mode    string `env:"MODE" default:"polling" validate:"oneof=polling webhook"`
+	}
+	t.Setenv("WORKERS", "32")
+	t.Setenv("MODE", "push")
+
+	// when
+	err := Load(&cfg, Options{})
+
+	// then
+	assert.EqualError(t, err, "Workers must be at most 16; Mode must be one of polling, webhook")
+}
+
+func TestLoad_RequiredTagIsValidationRule(t *testing.T) {
+	// given
+	var cfg struct {
+		Token  string `env:"TOKEN" required:"true"`
+		Secret string `env:"SECRET" required:"true" validate:"required"`
+		APIKey string `env:"API_KEY" validate:"required"`
+	}
+
+	// when
+	err := Load(&cfg, Options{})
+
+	// then
+	assert.ErrorIs(t, err, errs.Validation)
+	assert.EqualError(t, err, "Token (env TOKEN) is required; Secret is required; APIKey is required")
+}
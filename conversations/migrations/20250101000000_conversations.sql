@@ -0,0 +1,31 @@
+CREATE TABLE conversation_chats (
+    id VARCHAR(26) NOT NULL,
+    external_id VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX conversation_chats_external_id ON conversation_chats (external_id);
+
+CREATE TABLE conversation_messages (
+    id VARCHAR(26) NOT NULL,
+    chat_id VARCHAR(26) NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    content MEDIUMTEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX conversation_messages_chat ON conversation_messages (chat_id, id);
+
+CREATE TABLE conversation_usage (
+    id VARCHAR(26) NOT NULL,
+    chat_id VARCHAR(26) NOT NULL,
+    message_id VARCHAR(26) NOT NULL DEFAULT '',
+    model VARCHAR(255) NOT NULL,
+    input_tokens INT NOT NULL,
+    output_tokens INT NOT NULL,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX conversation_usage_created ON conversation_usage (created_at);
@@ -0,0 +1,269 @@
+// Package conversations persists chat history and token usage in sqldb, so conversations survive
+// restarts and can be queried for analytics.
+package conversations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+	"github.com/denis-kilchichakov/toolbox/ids"
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+var ErrNotFound = errs.New(errs.NotFound, "conversation not found")
+
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+	RoleTool      Role = "tool"
+)
+
+type Chat struct {
+	ID string
+	// ExternalID identifies the chat outside of the store, e.g. "telegram:123456".
+	ExternalID string
+	Title      string
+	CreatedAt  time.Time
+	// UpdatedAt is the time of the last message.
+	UpdatedAt time.Time
+}
+
+type Message struct {
+	ID        string
+	ChatID    string
+	Role      Role
+	Content   string
+	CreatedAt time.Time
+}
+
+// Usage is the token usage of a single model call.
+type Usage struct {
+	ChatID string
+	// MessageID optionally links the usage to the generated message.
+	MessageID    string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+}
+
+// UsageTotal aggregates usage of one model.
+type UsageTotal struct {
+	Model        string
+	Calls        int64
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// Store keeps conversations in the conversation_chats, conversation_messages and
+// conversation_usage tables.
+type Store struct {
+	db  *sqldb.SqlDb
+	now func() time.Time
+}
+
+// NewStore applies the package migrations, recorded in the migrations table like the
+// application's own, and returns a store on top of them.
+func NewStore(ctx context.Context, db *sqldb.SqlDb) (*Store, error) {
+	err := db.RunPackageMigrationsFSCtx(ctx, migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db, now: time.Now}, nil
+}
+
+// Chat returns the chat with the external ID, creating it if needed.
+func (s *Store) Chat(ctx context.Context, externalID string) (Chat, error) {
+	chat, err := s.chatByExternalID(ctx, externalID)
+	if err != ErrNotFound {
+		return chat, err
+	}
+
+	now := s.now()
+	chat = Chat{ID: ids.ULID(), ExternalID: externalID, CreatedAt: now, UpdatedAt: now}
+	_, err = s.db.ExecCtx(ctx,
+		"INSERT INTO conversation_chats (id, external_id, title, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)",
+		chat.ID, chat.ExternalID, chat.Title, now.UnixMilli(), now.UnixMilli(),
+	)
+	if err != nil {
+		// Another process may have created the chat concurrently.
+		if existing, getErr := s.chatByExternalID(ctx, externalID); getErr == nil {
+			return existing, nil
+		}
+		return Chat{}, err
+	}
+	return chat, nil
+}
+
+// GetChat returns ErrNotFound for unknown IDs.
+func (s *Store) GetChat(ctx context.Context, id string) (Chat, error) {
+	return s.scanChat(s.db.QueryRowCtx(ctx,
+		"SELECT id, external_id, title, created_at, updated_at FROM conversation_chats WHERE id = $1", id,
+	))
+}
+
+func (s *Store) SetTitle(ctx context.Context, chatID string, title string) error {
+	return s.db.WithTx(ctx, func(ctx context.Context, tx *sqldb.Tx) error {
+		if err := requireChat(ctx, tx, chatID); err != nil {
+			return err
+		}
+		_, err := tx.ExecCtx(ctx, "UPDATE conversation_chats SET title = $1 WHERE id = $2", title, chatID)
+		return err
+	})
+}
+
+// DeleteChat removes the chat with its messages. Usage records are kept for analytics.
+func (s *Store) DeleteChat(ctx context.Context, chatID string) error {
+	return s.db.WithTx(ctx, func(ctx context.Context, tx *sqldb.Tx) error {
+		_, err := tx.ExecCtx(ctx, "DELETE FROM conversation_messages WHERE chat_id = $1", chatID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecCtx(ctx, "DELETE FROM conversation_chats WHERE id = $1", chatID)
+		return err
+	})
+}
+
+// AppendMessage adds a message to the end of the chat.
+func (s *Store) AppendMessage(ctx context.Context, chatID string, role Role, content string) (Message, error) {
+	now := s.now()
+	msg := Message{ID: ids.ULID(), ChatID: chatID, Role: role, Content: content, CreatedAt: now}
+	err := s.db.WithTx(ctx, func(ctx context.Context, tx *sqldb.Tx) error {
+		if err := requireChat(ctx, tx, chatID); err != nil {
+			return err
+		}
+		_, err := tx.ExecCtx(ctx, "UPDATE conversation_chats SET updated_at = $1 WHERE id = $2", now.UnixMilli(), chatID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecCtx(ctx,
+			"INSERT INTO conversation_messages (id, chat_id, role, content, created_at) VALUES ($1, $2, $3, $4, $5)",
+			msg.ID, msg.ChatID, string(msg.Role), msg.Content, now.UnixMilli(),
+		)
+		return err
+	})
+	if err != nil {
+		return Message{}, err
+	}
+	return msg, nil
+}
+
+// Messages returns the last limit messages of the chat in chronological order, all of them if
+// limit is zero.
+func (s *Store) Messages(ctx context.Context, chatID string, limit int) ([]Message, error) {
+	query := "SELECT id, chat_id, role, content, created_at FROM conversation_messages WHERE chat_id = $1 ORDER BY id DESC"
+	args := []any{chatID}
+	if limit > 0 {
+		query += " LIMIT $2"
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryCtx(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []Message{}
+	for rows.Next() {
+		var msg Message
+		var role string
+		var createdAt int64
+		err := rows.Scan(&msg.ID, &msg.ChatID, &role, &msg.Content, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+		msg.Role = Role(role)
+		msg.CreatedAt = time.UnixMilli(createdAt)
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+// ClearMessages removes the history of the chat, keeping the chat itself.
+func (s *Store) ClearMessages(ctx context.Context, chatID string) error {
+	_, err := s.db.ExecCtx(ctx, "DELETE FROM conversation_messages WHERE chat_id = $1", chatID)
+	return err
+}
+
+func (s *Store) RecordUsage(ctx context.Context, u Usage) error {
+	_, err := s.db.ExecCtx(ctx,
+		"INSERT INTO conversation_usage (id, chat_id, message_id, model, input_tokens, output_tokens, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		ids.ULID(), u.ChatID, u.MessageID, u.Model, u.InputTokens, u.OutputTokens, s.now().UnixMilli(),
+	)
+	return err
+}
+
+// UsageSince returns usage per model recorded at or after since, ordered by model.
+func (s *Store) UsageSince(ctx context.Context, since time.Time) ([]UsageTotal, error) {
+	rows, err := s.db.QueryCtx(ctx, `
+		SELECT model, COUNT(*), SUM(input_tokens), SUM(output_tokens)
+		FROM conversation_usage
+		WHERE created_at >= $1
+		GROUP BY model
+		ORDER BY model`,
+		since.UnixMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []UsageTotal{}
+	for rows.Next() {
+		var t UsageTotal
+		if err := rows.Scan(&t.Model, &t.Calls, &t.InputTokens, &t.OutputTokens); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+func (s *Store) chatByExternalID(ctx context.Context, externalID string) (Chat, error) {
+	return s.scanChat(s.db.QueryRowCtx(ctx,
+		"SELECT id, external_id, title, created_at, updated_at FROM conversation_chats WHERE external_id = $1", externalID,
+	))
+}
+
+func (s *Store) scanChat(row *sql.Row) (Chat, error) {
+	var chat Chat
+	var createdAt, updatedAt int64
+	err := row.Scan(&chat.ID, &chat.ExternalID, &chat.Title, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return Chat{}, ErrNotFound
+	} else if err != nil {
+		return Chat{}, err
+	}
+	chat.CreatedAt = time.UnixMilli(createdAt)
+	chat.UpdatedAt = time.UnixMilli(updatedAt)
+	return chat, nil
+}
+
+// requireChat returns ErrNotFound for unknown chats. RowsAffected of an UPDATE can't be used for
+// that, MySQL doesn't count rows which already have the new values.
+func requireChat(ctx context.Context, tx *sqldb.Tx, chatID string) error {
+	var n int
+	err := tx.QueryRowCtx(ctx, "SELECT COUNT(*) FROM conversation_chats WHERE id = $1", chatID).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package conversations
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T) *Store {
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	db, err := sqldb.InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	store, err := NewStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	return store
+}
+
+func TestStoreChat(t *testing.T) {
+	// given
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// when
+	created, err := store.Chat(ctx, "telegram:1")
+	assert.NoError(t, err)
+	again, err := store.Chat(ctx, "telegram:1")
+	assert.NoError(t, err)
+	other, err := store.Chat(ctx, "telegram:2")
+	assert.NoError(t, err)
+	assert.NoError(t, store.SetTitle(ctx, created.ID, "Trip planning"))
+
+	// then
+	assert.Equal(t, created.ID, again.ID)
+	assert.NotEqual(t, created.ID, other.ID)
+	got, err := store.GetChat(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Trip planning", got.Title)
+	assert.Equal(t, "telegram:1", got.ExternalID)
+
+	_, err = store.GetChat(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.SetTitle(ctx, "missing", "x"), ErrNotFound)
+}
+
+func TestStoreMessages(t *testing.T) {
+	// given
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+	store.now = func() time.Time { return now }
+	chat, err := store.Chat(ctx, "telegram:1")
+	assert.NoError(t, err)
+
+	// when
+	for _, m := range []struct {
+		role    Role
+		content string
+	}{
+		{RoleSystem, "be brief"},
+		{RoleUser, "hi"},
+		{RoleAssistant, "hello"},
+		{RoleUser, "weather?"},
+	} {
+		now = now.Add(time.Second)
+		_, err := store.AppendMessage(ctx, chat.ID, m.role, m.content)
+		assert.NoError(t, err)
+	}
+
+	// then
+	all, err := store.Messages(ctx, chat.ID, 0)
+	assert.NoError(t, err)
+	assert.Len(t, all, 4)
+	assert.Equal(t, RoleSystem, all[0].Role)
+
+	last, err := store.Messages(ctx, chat.ID, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hello", "weather?"}, []string{last[0].Content, last[1].Content})
+	assert.True(t, now.Equal(last[1].CreatedAt))
+
+	got, err := store.GetChat(ctx, chat.ID)
+	assert.NoError(t, err)
+	assert.True(t, now.Equal(got.UpdatedAt))
+
+	_, err = store.AppendMessage(ctx, "missing", RoleUser, "hi")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStoreClearAndDelete(t *testing.T) {
+	// given
+	store := newTestStore(t)
+	ctx := context.Background()
+	chat, err := store.Chat(ctx, "telegram:1")
+	assert.NoError(t, err)
+	_, err = store.AppendMessage(ctx, chat.ID, RoleUser, "hi")
+	assert.NoError(t, err)
+
+	// when
+	assert.NoError(t, store.ClearMessages(ctx, chat.ID))
+	cleared, err := store.Messages(ctx, chat.ID, 0)
+	assert.NoError(t, err)
+	_, err = store.AppendMessage(ctx, chat.ID, RoleUser, "hi again")
+	assert.NoError(t, err)
+	assert.NoError(t, store.DeleteChat(ctx, chat.ID))
+
+	// then
+	assert.Empty(t, cleared)
+	_, err = store.GetChat(ctx, chat.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	deleted, err := store.Messages(ctx, chat.ID, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+}
+
+func TestStoreUsage(t *testing.T) {
+	// given
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+	store.now = func() time.Time { return now }
+
+	// when
+	assert.NoError(t, store.RecordUsage(ctx, Usage{ChatID: "c1", Model: "small", InputTokens: 1000, OutputTokens: 1}))
+	now = now.Add(time.Hour)
+	assert.NoError(t, store.RecordUsage(ctx, Usage{ChatID: "c1", Model: "small", InputTokens: 10, OutputTokens: 20}))
+	assert.NoError(t, store.RecordUsage(ctx, Usage{ChatID: "c2", MessageID: "m1", Model: "large", InputTokens: 5, OutputTokens: 7}))
+	assert.NoError(t, store.RecordUsage(ctx, Usage{ChatID: "c2", Model: "small", InputTokens: 1, OutputTokens: 2}))
+
+	// then
+	totals, err := store.UsageSince(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []UsageTotal{
+		{Model: "large", Calls: 1, InputTokens: 5, OutputTokens: 7},
+		{Model: "small", Calls: 2, InputTokens: 11, OutputTokens: 22},
+	}, totals)
+}
+
+func TestNewStoreIsIdempotent(t *testing.T) {
+	// given
+	store := newTestStore(t)
+
+	// when
+	_, err := NewStore(context.Background(), store.db)
+
+	// then
+	assert.NoError(t, err)
+}
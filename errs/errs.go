@@ -0,0 +1,89 @@
+// Package errs defines error kinds shared by toolbox packages, so callers can handle failures
+// uniformly regardless of where they come from:
+//
+//	if errors.Is(err, errs.NotFound) {
+//		...
+//	}
+//
+// Packages keep their own sentinel errors, created with New so that they match both the
+// sentinel and the kind.
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Kinds are compared with errors.Is.
+var (
+	NotFound     = errors.New("not found")
+	Unauthorized = errors.New("unauthorized")
+	RateLimited  = errors.New("rate limited")
+	Timeout      = errors.New("timeout")
+	Validation   = errors.New("validation failed")
+)
+
+var kinds = []error{NotFound, Unauthorized, RateLimited, Timeout, Validation}
+
+type kindError struct {
+	kind error
+	msg  string
+	err  error
+}
+
+func (e *kindError) Error() string {
+	if e.err == nil {
+		return e.msg
+	}
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	if e.err == nil {
+		return []error{e.kind}
+	}
+	return []error{e.err, e.kind}
+}
+
+// New returns an error with the message which matches kind, e.g. a package sentinel:
+//
+//	var ErrKeyNotFound = errs.New(errs.NotFound, "key not found")
+func New(kind error, msg string) error {
+	return &kindError{kind: kind, msg: msg}
+}
+
+// Wrap marks err with kind, keeping its message and chain. A nil err stays nil.
+func Wrap(kind error, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// Errorf is like fmt.Errorf, marking the result with kind.
+func Errorf(kind error, format string, args ...any) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// KindOf returns the kind of err, or nil if it has none. Context deadlines and errors with a
+// Timeout() bool method reporting true, such as net.Error, are classified as Timeout.
+func KindOf(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Timeout
+	}
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return Timeout
+	}
+	return nil
+}
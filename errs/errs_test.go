@@ -0,0 +1,64 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	// given
+	errKeyNotFound := New(NotFound, "key not found")
+
+	// when
+	err := fmt.Errorf("loading settings: %w", errKeyNotFound)
+
+	// then
+	assert.EqualError(t, err, "loading settings: key not found")
+	assert.ErrorIs(t, err, errKeyNotFound)
+	assert.ErrorIs(t, err, NotFound)
+	assert.NotErrorIs(t, err, Unauthorized)
+	assert.NotErrorIs(t, New(NotFound, "key not found"), errKeyNotFound)
+}
+
+func TestWrap(t *testing.T) {
+	// given
+	cause := &net.DNSError{Err: "no such host", Name: "api.example.com"}
+
+	// when
+	err := Wrap(Unauthorized, cause)
+
+	// then
+	assert.EqualError(t, err, cause.Error())
+	assert.ErrorIs(t, err, Unauthorized)
+	var dnsErr *net.DNSError
+	assert.ErrorAs(t, err, &dnsErr)
+	assert.NoError(t, Wrap(Unauthorized, nil))
+}
+
+func TestErrorf(t *testing.T) {
+	// given
+	cause := errors.New("status 429")
+
+	// when
+	err := Errorf(RateLimited, "search failed: %w", cause)
+
+	// then
+	assert.EqualError(t, err, "search failed: status 429")
+	assert.ErrorIs(t, err, RateLimited)
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestKindOf(t *testing.T) {
+	assert.Equal(t, NotFound, KindOf(fmt.Errorf("x: %w", New(NotFound, "missing"))))
+	assert.Equal(t, Validation, KindOf(Wrap(Validation, errors.New("Name is required"))))
+	assert.Equal(t, Timeout, KindOf(fmt.Errorf("x: %w", context.DeadlineExceeded)))
+	assert.Equal(t, Timeout, KindOf(&net.DNSError{IsTimeout: true}))
+	assert.Nil(t, KindOf(&net.DNSError{}))
+	assert.Nil(t, KindOf(errors.New("boom")))
+	assert.Nil(t, KindOf(nil))
+}
@@ -0,0 +1,174 @@
+// Package events is an in-process publish/subscribe bus with typed topics.
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+var ErrClosed = errors.New("event bus is closed")
+
+// Topic identifies a stream of events of type T. Declare topics as package-level variables:
+//
+//	var UserJoined = events.NewTopic[UserJoinedEvent]("user.joined")
+type Topic[T any] struct {
+	name string
+}
+
+func NewTopic[T any](name string) Topic[T] {
+	return Topic[T]{name: name}
+}
+
+func (t Topic[T]) Name() string {
+	return t.name
+}
+
+type subscriber struct {
+	ch chan any
+	// done is closed on unsubscribe and Close. ch is never closed, so publishers holding a stale
+	// snapshot of subscribers can't panic on send.
+	done    chan struct{}
+	handler func(ctx context.Context, event any)
+}
+
+// Bus delivers published events to subscribers. Every subscriber has its own buffer and
+// goroutine, so a slow subscriber delays only publishers of its topics once its buffer is full.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[string][]*subscriber
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subs: map[string][]*subscriber{},
+	}
+}
+
+// Subscribe calls handler for every event published to topic, in publishing order. Up to buffer
+// events are queued before publishers block. Handler panics are logged. unsubscribe stops the
+// subscription; already queued events are still handled.
+func Subscribe[T any](bus *Bus, topic Topic[T], buffer int, handler func(ctx context.Context, event T)) (unsubscribe func(), err error) {
+	sub := &subscriber{
+		ch:   make(chan any, buffer),
+		done: make(chan struct{}),
+		handler: func(ctx context.Context, event any) {
+			handler(ctx, event.(T))
+		},
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	if bus.closed {
+		return nil, ErrClosed
+	}
+	bus.subs[topic.name] = append(bus.subs[topic.name], sub)
+	bus.wg.Add(1)
+	go bus.run(topic.name, sub)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { bus.unsubscribe(topic.name, sub) })
+	}, nil
+}
+
+// Publish queues event for all subscribers of topic. It blocks while a subscriber's buffer is
+// full, until ctx is done or the subscriber is stopped by unsubscribe or Close.
+func Publish[T any](ctx context.Context, bus *Bus, topic Topic[T], event T) error {
+	bus.mu.RLock()
+	if bus.closed {
+		bus.mu.RUnlock()
+		return ErrClosed
+	}
+	subs := bus.subs[topic.name]
+	bus.mu.RUnlock()
+
+	for _, sub := range subs {
+		select {
+		case sub.ch <- event:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close stops accepting events and waits until subscribers handle queued ones or ctx is done.
+// Its signature matches system.CloseFunc.
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, subs := range b.subs {
+			for _, sub := range subs {
+				close(sub.done)
+			}
+		}
+		b.subs = map[string][]*subscriber{}
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bus) unsubscribe(topic string, sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	subs := b.subs[topic]
+	for i, s := range subs {
+		if s == sub {
+			b.subs[topic] = append(subs[:i:i], subs[i+1:]...)
+			close(sub.done)
+			return
+		}
+	}
+}
+
+func (b *Bus) run(topic string, sub *subscriber) {
+	defer b.wg.Done()
+	ctx := context.Background()
+	handle := func(event any) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Subscriber of %s panicked: %v\n%s", topic, r, debug.Stack())
+			}
+		}()
+		sub.handler(ctx, event)
+	}
+
+	for {
+		select {
+		case event := <-sub.ch:
+			handle(event)
+		case <-sub.done:
+			// Handle events queued before the subscription was stopped.
+			for {
+				select {
+				case event := <-sub.ch:
+					handle(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userJoined struct {
+	ID int64
+}
+
+var testTopic = NewTopic[userJoined]("user.joined")
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	// given
+	bus := NewBus()
+	ctx := context.Background()
+	var mu sync.Mutex
+	var first, second []int64
+	Subscribe(bus, testTopic, 10, func(ctx context.Context, e userJoined) {
+		mu.Lock()
+		defer mu.Unlock()
+		first = append(first, e.ID)
+	})
+	Subscribe(bus, testTopic, 0, func(ctx context.Context, e userJoined) {
+		mu.Lock()
+		defer mu.Unlock()
+		second = append(second, e.ID)
+	})
+	other := NewTopic[string]("other")
+	Subscribe(bus, other, 1, func(ctx context.Context, e string) {
+		t.Error("unexpected event on other topic")
+	})
+
+	// when
+	for i := int64(1); i <= 3; i++ {
+		assert.NoError(t, Publish(ctx, bus, testTopic, userJoined{ID: i}))
+	}
+	assert.NoError(t, bus.Close(ctx))
+
+	// then
+	assert.Equal(t, []int64{1, 2, 3}, first)
+	assert.Equal(t, []int64{1, 2, 3}, second)
+	assert.ErrorIs(t, Publish(ctx, bus, testTopic, userJoined{}), ErrClosed)
+	_, err := Subscribe(bus, testTopic, 0, func(ctx context.Context, e userJoined) {})
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.Equal(t, "user.joined", testTopic.Name())
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	// given
+	bus := NewBus()
+	ctx := context.Background()
+	received := make(chan int64, 10)
+	unsubscribe, _ := Subscribe(bus, testTopic, 10, func(ctx context.Context, e userJoined) {
+		received <- e.ID
+	})
+
+	// when
+	Publish(ctx, bus, testTopic, userJoined{ID: 1})
+	unsubscribe()
+	unsubscribe()
+	Publish(ctx, bus, testTopic, userJoined{ID: 2})
+	bus.Close(ctx)
+
+	// then
+	close(received)
+	ids := []int64{}
+	for id := range received {
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []int64{1}, ids)
+}
+
+func TestBus_PublishBlocksOnFullBuffer(t *testing.T) {
+	// given
+	bus := NewBus()
+	release := make(chan struct{})
+	Subscribe(bus, testTopic, 1, func(ctx context.Context, e userJoined) {
+		<-release
+	})
+	Publish(context.Background(), bus, testTopic, userJoined{ID: 1})
+	Publish(context.Background(), bus, testTopic, userJoined{ID: 2})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// when
+	err := Publish(ctx, bus, testTopic, userJoined{ID: 3})
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	close(release)
+	assert.NoError(t, bus.Close(context.Background()))
+}
+
+func TestBus_HandlerPanic(t *testing.T) {
+	// given
+	out := captureLog(t)
+	bus := NewBus()
+	handled := 0
+	Subscribe(bus, testTopic, 10, func(ctx context.Context, e userJoined) {
+		handled++
+		if e.ID == 1 {
+			panic("kaboom")
+		}
+	})
+
+	// when
+	Publish(context.Background(), bus, testTopic, userJoined{ID: 1})
+	Publish(context.Background(), bus, testTopic, userJoined{ID: 2})
+	bus.Close(context.Background())
+
+	// then
+	assert.Equal(t, 2, handled)
+	assert.Contains(t, out.String(), "Subscriber of user.joined panicked: kaboom")
+}
+
+func TestBus_CloseTimeout(t *testing.T) {
+	// given
+	bus := NewBus()
+	release := make(chan struct{})
+	defer close(release)
+	Subscribe(bus, testTopic, 1, func(ctx context.Context, e userJoined) {
+		<-release
+	})
+	Publish(context.Background(), bus, testTopic, userJoined{ID: 1})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// when
+	err := bus.Close(ctx)
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBus_CloseWithBlockedPublisher(t *testing.T) {
+	// given
+	bus := NewBus()
+	release := make(chan struct{})
+	defer close(release)
+	Subscribe(bus, testTopic, 1, func(ctx context.Context, e userJoined) {
+		<-release
+	})
+	Publish(context.Background(), bus, testTopic, userJoined{ID: 1})
+	Publish(context.Background(), bus, testTopic, userJoined{ID: 2})
+	published := make(chan error)
+	go func() {
+		published <- Publish(context.Background(), bus, testTopic, userJoined{ID: 3})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// when
+	err := bus.Close(ctx)
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	select {
+	case err := <-published:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("publisher is still blocked after Close")
+	}
+}
+
+func TestBus_UnsubscribeFromHandlerWithBlockedPublisher(t *testing.T) {
+	// given
+	bus := NewBus()
+	var unsubscribe func()
+	entered := make(chan struct{})
+	proceed := make(chan struct{})
+	unsubscribe, _ = Subscribe(bus, testTopic, 1, func(ctx context.Context, e userJoined) {
+		if e.ID == 1 {
+			close(entered)
+			<-proceed
+			unsubscribe()
+		}
+	})
+	Publish(context.Background(), bus, testTopic, userJoined{ID: 1})
+	<-entered
+	Publish(context.Background(), bus, testTopic, userJoined{ID: 2})
+	published := make(chan error)
+	go func() {
+		published <- Publish(context.Background(), bus, testTopic, userJoined{ID: 3})
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// when
+	close(proceed)
+
+	// then
+	select {
+	case err := <-published:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("bus deadlocked")
+	}
+	assert.NoError(t, bus.Close(context.Background()))
+}
@@ -0,0 +1,74 @@
+package featureflag
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshInterval is how long an Evaluator serves flags from memory before reloading them.
+const DefaultRefreshInterval = 30 * time.Second
+
+// Loader is implemented by SqlStore.
+type Loader interface {
+	Load(ctx context.Context) (map[string]Flag, error)
+}
+
+// Evaluator answers flag checks from an in-memory copy of the flags, reloaded from the store
+// once it is older than the refresh interval. Changes made in the store are therefore visible
+// with a delay of up to one interval in every process.
+type Evaluator struct {
+	loader   Loader
+	interval time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	flags    map[string]Flag
+	loadedAt time.Time
+}
+
+func NewEvaluator(loader Loader, refreshInterval time.Duration) *Evaluator {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+	return &Evaluator{
+		loader:   loader,
+		interval: refreshInterval,
+		now:      time.Now,
+	}
+}
+
+// Enabled reports whether the named flag is on for t. Unknown flags are off. If reloading fails,
+// the error is logged and the previously loaded flags are used.
+func (e *Evaluator) Enabled(ctx context.Context, name string, t Target) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.loadedAt.IsZero() || e.now().Sub(e.loadedAt) >= e.interval {
+		if err := e.refreshLocked(ctx); err != nil {
+			log.Println("Feature flags refresh failed: ", err)
+		}
+	}
+	flag, ok := e.flags[name]
+	return ok && flag.Enabled(t)
+}
+
+// Refresh reloads the flags immediately, e.g. right after changing them in this process.
+func (e *Evaluator) Refresh(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.refreshLocked(ctx)
+}
+
+func (e *Evaluator) refreshLocked(ctx context.Context) error {
+	// A failed load is retried only after another interval, so an unavailable store isn't
+	// queried on every check.
+	e.loadedAt = e.now()
+	flags, err := e.loader.Load(ctx)
+	if err != nil {
+		return err
+	}
+	e.flags = flags
+	return nil
+}
@@ -0,0 +1,88 @@
+package featureflag
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLoader struct {
+	flags map[string]Flag
+	err   error
+	loads int
+}
+
+func (l *fakeLoader) Load(ctx context.Context) (map[string]Flag, error) {
+	l.loads++
+	return l.flags, l.err
+}
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestEvaluatorCachesFlags(t *testing.T) {
+	// given
+	loader := &fakeLoader{flags: map[string]Flag{"voice": {Name: "voice", Rollout: 100}}}
+	e := NewEvaluator(loader, time.Minute)
+	now := time.Now()
+	e.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// when
+	first := e.Enabled(ctx, "voice", Target{UserID: 1})
+	loader.flags = map[string]Flag{}
+	cached := e.Enabled(ctx, "voice", Target{UserID: 1})
+	now = now.Add(time.Minute)
+	refreshed := e.Enabled(ctx, "voice", Target{UserID: 1})
+
+	// then
+	assert.True(t, first)
+	assert.True(t, cached)
+	assert.False(t, refreshed)
+	assert.Equal(t, 2, loader.loads)
+}
+
+func TestEvaluatorKeepsFlagsWhenRefreshFails(t *testing.T) {
+	// given
+	logs := captureLog(t)
+	loader := &fakeLoader{flags: map[string]Flag{"voice": {Name: "voice", Rollout: 100}}}
+	e := NewEvaluator(loader, time.Minute)
+	now := time.Now()
+	e.now = func() time.Time { return now }
+	ctx := context.Background()
+	assert.NoError(t, e.Refresh(ctx))
+
+	// when
+	loader.err = errors.New("db is down")
+	now = now.Add(time.Minute)
+	enabled := e.Enabled(ctx, "voice", Target{UserID: 1})
+	enabledAgain := e.Enabled(ctx, "voice", Target{UserID: 1})
+
+	// then
+	assert.True(t, enabled)
+	assert.True(t, enabledAgain)
+	assert.Equal(t, 2, loader.loads)
+	assert.Contains(t, logs.String(), "db is down")
+}
+
+func TestEvaluatorUnknownFlag(t *testing.T) {
+	// given
+	e := NewEvaluator(&fakeLoader{}, 0)
+
+	// when
+	enabled := e.Enabled(context.Background(), "missing", Target{UserID: 1})
+
+	// then
+	assert.False(t, enabled)
+	assert.Equal(t, DefaultRefreshInterval, e.interval)
+}
@@ -0,0 +1,64 @@
+// Package featureflag toggles features at runtime: a flag is rolled out to a percentage of users
+// or chats, and can be forced on or off for individual ones.
+package featureflag
+
+import (
+	"hash/fnv"
+	"strconv"
+)
+
+// Kind is the kind of subject an override applies to.
+type Kind string
+
+const (
+	KindUser Kind = "user"
+	KindChat Kind = "chat"
+)
+
+// Target identifies who a flag is evaluated for. Zero IDs are unknown.
+type Target struct {
+	UserID int64
+	ChatID int64
+}
+
+type Flag struct {
+	Name string
+	// Rollout is the percentage of targets the flag is enabled for, from 0 to 100.
+	Rollout int
+	// UserOverrides and ChatOverrides force the flag on or off for individual users and chats.
+	UserOverrides map[int64]bool
+	ChatOverrides map[int64]bool
+}
+
+// Enabled checks the user override, then the chat override, then the rollout percentage.
+// Rollout buckets are derived from the user ID, or the chat ID when the user is unknown, so the
+// same target gets a stable answer as the percentage grows.
+func (f Flag) Enabled(t Target) bool {
+	if enabled, ok := f.UserOverrides[t.UserID]; ok && t.UserID != 0 {
+		return enabled
+	}
+	if enabled, ok := f.ChatOverrides[t.ChatID]; ok && t.ChatID != 0 {
+		return enabled
+	}
+
+	if f.Rollout >= 100 {
+		return true
+	}
+	if f.Rollout <= 0 {
+		return false
+	}
+	id := t.UserID
+	if id == 0 {
+		id = t.ChatID
+	}
+	if id == 0 {
+		return false
+	}
+	return bucket(f.Name, id) < f.Rollout
+}
+
+func bucket(name string, id int64) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + strconv.FormatInt(id, 10)))
+	return int(h.Sum32() % 100)
+}
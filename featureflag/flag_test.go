@@ -0,0 +1,57 @@
+package featureflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagEnabledOverrides(t *testing.T) {
+	// given
+	f := Flag{
+		Name:          "voice",
+		Rollout:       0,
+		UserOverrides: map[int64]bool{1: true, 2: false},
+		ChatOverrides: map[int64]bool{10: true},
+	}
+
+	// when, then
+	assert.True(t, f.Enabled(Target{UserID: 1}))
+	assert.False(t, f.Enabled(Target{UserID: 2, ChatID: 10}))
+	assert.True(t, f.Enabled(Target{UserID: 3, ChatID: 10}))
+	assert.False(t, f.Enabled(Target{UserID: 3, ChatID: 11}))
+}
+
+func TestFlagEnabledRollout(t *testing.T) {
+	// given
+	f := Flag{Name: "voice", Rollout: 30}
+
+	// when
+	enabled := 0
+	for id := int64(1); id <= 10000; id++ {
+		if f.Enabled(Target{UserID: id}) {
+			enabled++
+		}
+	}
+
+	// then
+	assert.InDelta(t, 3000, enabled, 300)
+	assert.False(t, f.Enabled(Target{}))
+	assert.True(t, Flag{Rollout: 100}.Enabled(Target{}))
+}
+
+func TestFlagRolloutIsStableWhenGrowing(t *testing.T) {
+	// given
+	small := Flag{Name: "voice", Rollout: 10}
+	large := Flag{Name: "voice", Rollout: 50}
+
+	for id := int64(1); id <= 1000; id++ {
+		// when
+		target := Target{ChatID: id}
+
+		// then
+		if small.Enabled(target) {
+			assert.True(t, large.Enabled(target), id)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+)
+
+var sqlStoreInitialScripts = []string{`
+CREATE TABLE IF NOT EXISTS feature_flags (
+    name VARCHAR(255) NOT NULL,
+    rollout INT NOT NULL,
+    PRIMARY KEY (name)
+);
+`, `
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+    flag VARCHAR(255) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    subject_id BIGINT NOT NULL,
+    enabled TINYINT NOT NULL,
+    PRIMARY KEY (flag, kind, subject_id)
+);
+`}
+
+// SqlStore keeps flags in the feature_flags and feature_flag_overrides tables.
+type SqlStore struct {
+	db *sqldb.SqlDb
+}
+
+// NewSqlStore creates the tables if needed and returns a store on top of them.
+func NewSqlStore(ctx context.Context, db *sqldb.SqlDb) (*SqlStore, error) {
+	for _, script := range sqlStoreInitialScripts {
+		_, err := db.ExecCtx(ctx, script)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &SqlStore{db: db}, nil
+}
+
+// SetRollout creates the flag or changes its rollout percentage.
+func (s *SqlStore) SetRollout(ctx context.Context, name string, percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("rollout must be between 0 and 100, got: %d", percent)
+	}
+	_, err := s.db.ExecCtx(ctx, "REPLACE INTO feature_flags (name, rollout) VALUES ($1, $2)", name, percent)
+	return err
+}
+
+// SetOverride forces the flag on or off for a user or a chat. The flag doesn't need to exist,
+// flags without a rollout row are off for everyone else.
+func (s *SqlStore) SetOverride(ctx context.Context, name string, kind Kind, id int64, enabled bool) error {
+	if kind != KindUser && kind != KindChat {
+		return fmt.Errorf("unknown override kind: %q", kind)
+	}
+	_, err := s.db.ExecCtx(ctx,
+		"REPLACE INTO feature_flag_overrides (flag, kind, subject_id, enabled) VALUES ($1, $2, $3, $4)",
+		name, string(kind), id, enabled,
+	)
+	return err
+}
+
+func (s *SqlStore) DeleteOverride(ctx context.Context, name string, kind Kind, id int64) error {
+	_, err := s.db.ExecCtx(ctx,
+		"DELETE FROM feature_flag_overrides WHERE flag = $1 AND kind = $2 AND subject_id = $3",
+		name, string(kind), id,
+	)
+	return err
+}
+
+// Delete removes the flag with all its overrides.
+func (s *SqlStore) Delete(ctx context.Context, name string) error {
+	return s.db.WithTx(ctx, func(ctx context.Context, tx *sqldb.Tx) error {
+		_, err := tx.ExecCtx(ctx, "DELETE FROM feature_flag_overrides WHERE flag = $1", name)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecCtx(ctx, "DELETE FROM feature_flags WHERE name = $1", name)
+		return err
+	})
+}
+
+// Load returns all flags by name, including flags which only have overrides.
+func (s *SqlStore) Load(ctx context.Context) (map[string]Flag, error) {
+	flags := map[string]Flag{}
+	flag := func(name string) Flag {
+		f, ok := flags[name]
+		if !ok {
+			f = Flag{Name: name, UserOverrides: map[int64]bool{}, ChatOverrides: map[int64]bool{}}
+		}
+		return f
+	}
+
+	rows, err := s.db.QueryCtx(ctx, "SELECT name, rollout FROM feature_flags")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var rollout int
+		if err := rows.Scan(&name, &rollout); err != nil {
+			return nil, err
+		}
+		f := flag(name)
+		f.Rollout = rollout
+		flags[name] = f
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.QueryCtx(ctx, "SELECT flag, kind, subject_id, enabled FROM feature_flag_overrides")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, kind string
+		var id int64
+		var enabled bool
+		if err := rows.Scan(&name, &kind, &id, &enabled); err != nil {
+			return nil, err
+		}
+		f := flag(name)
+		switch Kind(kind) {
+		case KindUser:
+			f.UserOverrides[id] = enabled
+		case KindChat:
+			f.ChatOverrides[id] = enabled
+		}
+		flags[name] = f
+	}
+	return flags, rows.Err()
+}
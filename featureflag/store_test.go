@@ -0,0 +1,83 @@
+package featureflag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T) *SqlStore {
+	db, err := sqldb.InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	store, err := NewSqlStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("NewSqlStore failed: %v", err)
+	}
+	return store
+}
+
+func TestSqlStore(t *testing.T) {
+	// given
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// when
+	assert.NoError(t, store.SetRollout(ctx, "voice", 20))
+	assert.NoError(t, store.SetRollout(ctx, "voice", 50))
+	assert.NoError(t, store.SetOverride(ctx, "voice", KindUser, 1, true))
+	assert.NoError(t, store.SetOverride(ctx, "voice", KindChat, -100, false))
+	assert.NoError(t, store.SetOverride(ctx, "beta", KindUser, 2, true))
+	assert.NoError(t, store.SetOverride(ctx, "beta", KindUser, 3, true))
+	assert.NoError(t, store.DeleteOverride(ctx, "beta", KindUser, 3))
+
+	// then
+	flags, err := store.Load(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Flag{
+		"voice": {
+			Name:          "voice",
+			Rollout:       50,
+			UserOverrides: map[int64]bool{1: true},
+			ChatOverrides: map[int64]bool{-100: false},
+		},
+		"beta": {
+			Name:          "beta",
+			UserOverrides: map[int64]bool{2: true},
+			ChatOverrides: map[int64]bool{},
+		},
+	}, flags)
+}
+
+func TestSqlStoreDelete(t *testing.T) {
+	// given
+	store := newTestStore(t)
+	ctx := context.Background()
+	assert.NoError(t, store.SetRollout(ctx, "voice", 50))
+	assert.NoError(t, store.SetOverride(ctx, "voice", KindUser, 1, true))
+
+	// when
+	err := store.Delete(ctx, "voice")
+
+	// then
+	assert.NoError(t, err)
+	flags, err := store.Load(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, flags)
+}
+
+func TestSqlStoreRejectsInvalidValues(t *testing.T) {
+	// given
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// when, then
+	assert.Error(t, store.SetRollout(ctx, "voice", 101))
+	assert.Error(t, store.SetRollout(ctx, "voice", -1))
+	assert.Error(t, store.SetOverride(ctx, "voice", Kind("group"), 1, true))
+}
@@ -0,0 +1,60 @@
+package fileutil
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "state.json")
+	assert.NoError(t, os.WriteFile(path, []byte("old"), 0o644))
+
+	// when
+	err := WriteFileAtomic(path, []byte("new"), 0o600)
+
+	// then
+	assert.NoError(t, err)
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "new", string(data))
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, entries, 1, "no temp files are left")
+}
+
+func TestWriteAtomic_Failure(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "state.json")
+	assert.NoError(t, os.WriteFile(path, []byte("old"), 0o644))
+	boom := errors.New("boom")
+
+	// when
+	err := WriteAtomic(path, 0o644, func(w io.Writer) error {
+		w.Write([]byte("partial"))
+		return boom
+	})
+
+	// then
+	assert.ErrorIs(t, err, boom)
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "old", string(data))
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, entries, 1)
+}
+
+func TestSyncDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directories are not synced on windows")
+	}
+	dir := t.TempDir()
+
+	assert.NoError(t, syncDir(dir))
+	assert.ErrorIs(t, syncDir(filepath.Join(dir, "missing")), os.ErrNotExist)
+}
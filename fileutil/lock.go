@@ -0,0 +1,50 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const lockFileName = ".lock"
+
+var ErrLocked = errors.New("directory is locked by another process")
+
+// DirLock is an exclusive lock on a directory, e.g. a data directory a single bot instance owns.
+type DirLock struct {
+	f *os.File
+}
+
+// LockDir takes an exclusive lock on dir, creating it if needed. It returns ErrLocked if another
+// process holds the lock. The lock is released by Unlock or when the process exits.
+func LockDir(dir string) (*DirLock, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, lockFileName), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	err = lockFile(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	f.Truncate(0)
+	fmt.Fprintf(f, "%d\n", os.Getpid())
+	return &DirLock{f: f}, nil
+}
+
+func (l *DirLock) Unlock() error {
+	if l.f == nil {
+		return nil
+	}
+	err := unlockFile(l.f)
+	closeErr := l.f.Close()
+	l.f = nil
+	return errors.Join(err, closeErr)
+}
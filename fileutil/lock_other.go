@@ -0,0 +1,16 @@
+//go:build !linux && !darwin
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+)
+
+func lockFile(f *os.File) error {
+	return errors.New("directory locking is not supported on this platform")
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build linux || darwin
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockDir(t *testing.T) {
+	// given
+	dir := filepath.Join(t.TempDir(), "data")
+
+	// when
+	lock, err := LockDir(dir)
+
+	// then
+	assert.NoError(t, err)
+	pid, _ := os.ReadFile(filepath.Join(dir, lockFileName))
+	assert.Equal(t, strconv.Itoa(os.Getpid()), strings.TrimSpace(string(pid)))
+
+	_, err = LockDir(dir)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	assert.NoError(t, lock.Unlock())
+	assert.NoError(t, lock.Unlock())
+
+	again, err := LockDir(dir)
+	assert.NoError(t, err)
+	again.Unlock()
+}
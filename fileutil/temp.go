@@ -0,0 +1,48 @@
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TempDir creates a new temporary directory in parent (os.TempDir() if empty) and returns a
+// function removing it with all contents.
+func TempDir(parent, pattern string) (dir string, cleanup func() error, err error) {
+	dir, err = os.MkdirTemp(parent, pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	return dir, func() error { return os.RemoveAll(dir) }, nil
+}
+
+// RemoveStale removes entries of dir matching the glob pattern that weren't modified for
+// olderThan, e.g. leftovers of downloads interrupted by a crash. It returns the number of
+// removed entries.
+func RemoveStale(dir, pattern string, olderThan time.Duration) (int, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+	var errs []error
+	for _, path := range matches {
+		info, err := os.Lstat(path)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(cutoff) {
+			continue
+		}
+		err = os.RemoveAll(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed++
+	}
+	return removed, errors.Join(errs...)
+}
@@ -0,0 +1,50 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTempDir(t *testing.T) {
+	// given
+	dir, cleanup, err := TempDir(t.TempDir(), "download-*")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "file"), []byte("data"), 0o644))
+
+	// when
+	err = cleanup()
+
+	// then
+	assert.NoError(t, err)
+	_, err = os.Stat(dir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRemoveStale(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	old := filepath.Join(dir, "download-old")
+	fresh := filepath.Join(dir, "download-fresh")
+	other := filepath.Join(dir, "bot.db")
+	for _, path := range []string{old, fresh, other} {
+		assert.NoError(t, os.WriteFile(path, nil, 0o644))
+	}
+	past := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(old, past, past))
+	assert.NoError(t, os.Chtimes(other, past, past))
+
+	// when
+	removed, err := RemoveStale(dir, "download-*", time.Hour)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	_, err = os.Stat(old)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.FileExists(t, fresh)
+	assert.FileExists(t, other)
+}
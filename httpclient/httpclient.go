@@ -0,0 +1,78 @@
+// Package httpclient builds *http.Client instances with timeouts, retries, proxy support,
+// logging and instrumentation.
+package httpclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/metrics"
+	"github.com/denis-kilchichakov/toolbox/retry"
+)
+
+type Options struct {
+	// Timeout bounds a whole request including retries and reading the body.
+	Timeout time.Duration
+	// Retry enables retries of failed requests, see RetryTransport.
+	Retry *retry.Policy
+	// Proxy is a proxy URL; empty means the HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars are used.
+	Proxy string
+	// Log enables logging of every request, see LoggingTransport.
+	Log bool
+	// Observer receives outcomes of every attempt, see ObserverTransport.
+	Observer metrics.Observer
+}
+
+func DefaultOptions() Options {
+	policy := retry.DefaultPolicy()
+	return Options{
+		Timeout: time.Minute,
+		Retry:   &policy,
+	}
+}
+
+// New returns a client configured according to opts. Round-trippers are layered so that every
+// retry attempt is logged and observed separately.
+func New(opts Options) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.ResponseHeaderTimeout = opts.Timeout
+
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	var rt http.RoundTripper = transport
+	if opts.Observer != nil {
+		rt = ObserverTransport(rt, opts.Observer)
+	}
+	if opts.Log {
+		rt = LoggingTransport(rt)
+	}
+	if opts.Retry != nil {
+		rt = RetryTransport(rt, *opts.Retry)
+	}
+
+	return &http.Client{
+		Transport: rt,
+		Timeout:   opts.Timeout,
+	}, nil
+}
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,81 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	// when
+	client, err := New(DefaultOptions())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, client.Timeout)
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "pong", string(body))
+}
+
+func TestNew_Proxy(t *testing.T) {
+	// given
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	// when
+	client, err := New(Options{Proxy: proxy.URL})
+	assert.NoError(t, err)
+	resp, err := client.Get("http://api.example.com/v1")
+
+	// then
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "http://api.example.com/v1", proxied)
+
+	_, err = New(Options{Proxy: "://bad"})
+	var urlErr *url.Error
+	assert.ErrorAs(t, err, &urlErr)
+}
+
+func TestNew_RetriesAreObserved(t *testing.T) {
+	// given
+	captureLog(t)
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	observer := &recordingObserver{}
+	policy := retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+
+	// when
+	client, _ := New(Options{Retry: &policy, Log: true, Observer: observer})
+	resp, err := client.Get(server.URL)
+
+	// then
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"error", "ok"}, observer.statuses)
+}
@@ -0,0 +1,155 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/metrics"
+	"github.com/denis-kilchichakov/toolbox/retry"
+)
+
+// retryableStatus is returned inside the retry loop for responses worth retrying.
+type retryableStatus struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e *retryableStatus) Error() string {
+	return fmt.Sprintf("retryable status %d", e.status)
+}
+
+func (e *retryableStatus) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// IdempotencyKeyHeader marks a request as safe to retry whatever its method.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotentKey struct{}
+
+// WithIdempotent marks requests made with the returned context as safe to retry, so RetryTransport
+// retries them even if their method is not idempotent, e.g. a POST the server deduplicates.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// RetryTransport retries requests failing with a network error or with status 429, 502, 503 or
+// 504, honoring the Retry-After header. Requests with a body are retried only if it can be
+// replayed, i.e. req.GetBody is set, as http.NewRequest does for in-memory bodies. When retries
+// are exhausted, the last response is returned as is.
+// Only idempotent methods (GET, HEAD, OPTIONS, TRACE, PUT and DELETE) are retried, unless the
+// request has an Idempotency-Key header or its context comes from WithIdempotent, so a write
+// isn't duplicated when its response is lost.
+func RetryTransport(next http.RoundTripper, policy retry.Policy) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if !retryable(req) {
+			return next.RoundTrip(req)
+		}
+
+		var last *http.Response
+		attempt := 0
+		resp, err := retry.DoValue(req.Context(), policy, func(ctx context.Context) (*http.Response, error) {
+			if last != nil {
+				io.Copy(io.Discard, last.Body)
+				last.Body.Close()
+				last = nil
+			}
+
+			attemptReq := req
+			if attempt > 0 && req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, retry.Permanent(err)
+				}
+				attemptReq = req.Clone(ctx)
+				attemptReq.Body = body
+			}
+			attempt++
+
+			resp, err := next.RoundTrip(attemptReq)
+			if err != nil {
+				return nil, err
+			}
+			switch resp.StatusCode {
+			case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				last = resp
+				return resp, &retryableStatus{status: resp.StatusCode, retryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+			}
+			return resp, nil
+		})
+
+		var status *retryableStatus
+		if errors.As(err, &status) && last != nil {
+			if req.Context().Err() != nil {
+				last.Body.Close()
+				return nil, req.Context().Err()
+			}
+			return last, nil
+		}
+		return resp, err
+	})
+}
+
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if req.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	idempotent, _ := req.Context().Value(idempotentKey{}).(bool)
+	return idempotent
+}
+
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// LoggingTransport logs method, URL without query, status and duration of every request.
+func LoggingTransport(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		target := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+		if err != nil {
+			log.Printf("HTTP %s %s failed after %s: %v", req.Method, target, time.Since(start), err)
+			return resp, err
+		}
+		log.Printf("HTTP %s %s %d %s", req.Method, target, resp.StatusCode, time.Since(start))
+		return resp, nil
+	})
+}
+
+// ObserverTransport reports every request to observer with operation "<METHOD> <host>".
+// Responses with status 5xx are reported as errors.
+func ObserverTransport(next http.RoundTripper, observer metrics.Observer) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		observed := err
+		if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+			observed = errors.New(resp.Status)
+		}
+		observer.Observe(req.Method+" "+req.URL.Host, time.Since(start), observed)
+		return resp, err
+	})
+}
@@ -0,0 +1,202 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingObserver struct {
+	operations []string
+	statuses   []string
+}
+
+func (o *recordingObserver) Observe(operation string, duration time.Duration, err error) {
+	o.operations = append(o.operations, operation)
+	if err != nil {
+		o.statuses = append(o.statuses, "error")
+	} else {
+		o.statuses = append(o.statuses, "ok")
+	}
+}
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func testPolicy() retry.Policy {
+	return retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+}
+
+func TestRetryTransport_ReplaysBody(t *testing.T) {
+	// given
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: RetryTransport(http.DefaultTransport, testPolicy())}
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+
+	// when
+	resp, err := client.Do(req)
+
+	// then
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"payload", "payload", "payload"}, bodies)
+}
+
+func TestRetryTransport_NonIdempotentMethods(t *testing.T) {
+	// given
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: RetryTransport(http.DefaultTransport, testPolicy())}
+
+	attemptsFor := func(req *http.Request) int {
+		attempts = 0
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return attempts
+	}
+	plain, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	withKey, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	withKey.Header.Set(IdempotencyKeyHeader, "key-1")
+	optedIn, _ := http.NewRequestWithContext(WithIdempotent(context.Background()), http.MethodPatch, server.URL, strings.NewReader("payload"))
+
+	// when
+	plainAttempts, withKeyAttempts, optedInAttempts := attemptsFor(plain), attemptsFor(withKey), attemptsFor(optedIn)
+
+	// then
+	assert.Equal(t, 1, plainAttempts)
+	assert.Equal(t, 3, withKeyAttempts)
+	assert.Equal(t, 3, optedInAttempts)
+}
+
+func TestRetryTransport_ReturnsLastResponse(t *testing.T) {
+	// given
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("upstream down"))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: RetryTransport(http.DefaultTransport, testPolicy())}
+
+	// when
+	resp, err := client.Get(server.URL)
+
+	// then
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "upstream down", string(body))
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	// given
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: RetryTransport(http.DefaultTransport, testPolicy())}
+
+	// when
+	resp, err := client.Get(server.URL)
+
+	// then
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryTransport_NetworkError(t *testing.T) {
+	// given
+	attempts := 0
+	failing := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errors.New("connection reset")
+	})
+	client := &http.Client{Transport: RetryTransport(failing, testPolicy())}
+
+	// when
+	_, err := client.Get("http://example.com")
+
+	// then
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, 2*time.Second, retryAfter("2"))
+	assert.Zero(t, retryAfter(""))
+	assert.Zero(t, retryAfter("soon"))
+	assert.InDelta(t, 10*time.Second, retryAfter(time.Now().Add(10*time.Second).UTC().Format(http.TimeFormat)), float64(time.Second))
+}
+
+func TestLoggingTransport(t *testing.T) {
+	// given
+	out := captureLog(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: LoggingTransport(http.DefaultTransport)}
+
+	// when
+	resp, err := client.Get(server.URL + "/path?token=secret")
+
+	// then
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Contains(t, out.String(), "HTTP GET "+server.URL+"/path 204 ")
+	assert.NotContains(t, out.String(), "secret")
+}
+
+func TestObserverTransport(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	observer := &recordingObserver{}
+	client := &http.Client{Transport: ObserverTransport(http.DefaultTransport, observer)}
+
+	// when
+	resp, err := client.Get(server.URL)
+
+	// then
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []string{"GET " + strings.TrimPrefix(server.URL, "http://")}, observer.operations)
+	assert.Equal(t, []string{"error"}, observer.statuses)
+}
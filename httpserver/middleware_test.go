@@ -0,0 +1,114 @@
+package httpserver
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestMiddleware(t *testing.T) {
+	// given
+	out := captureLog(t)
+	var seenID string
+	handler := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenID = RequestIDFromContext(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	}), DefaultOptions("")).handler
+
+	// when
+	req := httptest.NewRequest(http.MethodGet, "/tea", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	// then
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, "req-1", seenID)
+	assert.Equal(t, "req-1", rec.Header().Get(RequestIDHeader))
+	assert.Contains(t, out.String(), "GET /tea 418 ")
+	assert.Contains(t, out.String(), "request_id=req-1")
+}
+
+func TestRequestID_Generated(t *testing.T) {
+	// given
+	var seenID string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenID = RequestIDFromContext(r.Context())
+	}))
+
+	// when
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// then
+	assert.Len(t, seenID, 26)
+	assert.Equal(t, seenID, rec.Header().Get(RequestIDHeader))
+}
+
+func TestRequestID_InvalidClientIDIsReplaced(t *testing.T) {
+	for _, id := range []string{"bad id\nINFO forged", strings.Repeat("a", 129), "<script>"} {
+		// given
+		var seenID string
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seenID = RequestIDFromContext(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, id)
+
+		// when
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		// then
+		assert.Len(t, seenID, 26, id)
+		assert.Equal(t, seenID, rec.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	// given
+	out := captureLog(t)
+	handler := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("kaboom")
+	}))
+
+	// when
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/boom", nil))
+
+	// then
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, out.String(), "Panic serving POST /boom: kaboom")
+}
+
+func TestChain(t *testing.T) {
+	// given
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	// when
+	Chain(http.NotFoundHandler(), mw("a"), mw("b")).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// then
+	assert.Equal(t, []string{"a", "b"}, order)
+}
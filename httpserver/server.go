@@ -0,0 +1,129 @@
+// Package httpserver runs HTTP servers with sane timeouts, common middleware and graceful shutdown.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type Options struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout bounds draining of in-flight requests in Run.
+	ShutdownTimeout time.Duration
+}
+
+func DefaultOptions(addr string) Options {
+	return Options{
+		Addr:              addr,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		ShutdownTimeout:   10 * time.Second,
+	}
+}
+
+type Server struct {
+	opts    Options
+	handler http.Handler
+
+	mu       sync.Mutex
+	server   *http.Server
+	listener net.Listener
+}
+
+// New returns a server for handler wrapped with RequestID, Logging and Recovery middleware.
+func New(handler http.Handler, opts Options) *Server {
+	return NewWithMiddleware(handler, opts, RequestID, Logging, Recovery)
+}
+
+// NewWithMiddleware returns a server for handler wrapped with the given middleware only, e.g. to
+// skip request logging for frequently polled endpoints.
+func NewWithMiddleware(handler http.Handler, opts Options, middleware ...Middleware) *Server {
+	return &Server{
+		opts:    opts,
+		handler: Chain(handler, middleware...),
+	}
+}
+
+// Start binds the address and serves in background.
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server != nil {
+		return errors.New("http server is already started")
+	}
+	listener, err := net.Listen("tcp", s.opts.Addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	s.server = &http.Server{
+		Handler:           s.handler,
+		ReadHeaderTimeout: s.opts.ReadHeaderTimeout,
+		ReadTimeout:       s.opts.ReadTimeout,
+		WriteTimeout:      s.opts.WriteTimeout,
+		IdleTimeout:       s.opts.IdleTimeout,
+	}
+
+	go func(server *http.Server) {
+		err := server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			log.Println("HTTP server failed: ", err)
+		}
+	}(s.server)
+	return nil
+}
+
+// Addr returns the bound address after Start, useful when listening on port 0.
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return s.opts.Addr
+	}
+	return s.listener.Addr().String()
+}
+
+// Stop gracefully shuts the server down, waiting for in-flight requests until ctx is done.
+// Its signature matches system.CloseFunc.
+func (s *Server) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.listener = nil
+	s.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
+
+// Run starts the server and stops it within ShutdownTimeout once ctx is done, so it can be
+// passed to system.Run.
+func (s *Server) Run(ctx context.Context) error {
+	err := s.Start()
+	if err != nil {
+		return err
+	}
+	<-ctx.Done()
+
+	stopCtx := context.WithoutCancel(ctx)
+	if s.opts.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		stopCtx, cancel = context.WithTimeout(stopCtx, s.opts.ShutdownTimeout)
+		defer cancel()
+	}
+	return s.Stop(stopCtx)
+}
@@ -0,0 +1,70 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Run(t *testing.T) {
+	// given
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte("done"))
+	})
+	s := New(mux, DefaultOptions("127.0.0.1:0"))
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Run(ctx) }()
+	waitForAddr(t, s)
+
+	// when
+	responses := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + s.Addr() + "/slow")
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		responses <- string(body)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	// then
+	assert.Equal(t, "done", <-responses, "in-flight request is drained")
+	assert.NoError(t, <-stopped)
+}
+
+func TestServer_StartTwice(t *testing.T) {
+	// given
+	s := New(http.NotFoundHandler(), DefaultOptions("127.0.0.1:0"))
+	defer s.Stop(context.Background())
+
+	// when
+	err := s.Start()
+
+	// then
+	assert.NoError(t, err)
+	assert.Error(t, s.Start())
+}
+
+func waitForAddr(t *testing.T, s *Server) {
+	for i := 0; i < 100; i++ {
+		if s.Addr() != "127.0.0.1:0" {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("server did not start")
+}
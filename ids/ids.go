@@ -0,0 +1,147 @@
+// Package ids generates identifiers for requests, jobs and conversations.
+//
+// UUIDv7 and ULID values start with a millisecond timestamp and sort in creation order, also for
+// IDs generated within the same millisecond by one process. Short IDs are random and meant for
+// user-visible references where length matters more than ordering.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base62    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// ShortLength is the length of IDs from Short, ~71 bits of entropy.
+const ShortLength = 12
+
+var (
+	ulidGen = generator{hiBits: 16, loBits: 64, now: time.Now}
+	uuidGen = generator{hiBits: 12, loBits: 62, now: time.Now}
+)
+
+// UUIDv7 returns an RFC 9562 version 7 UUID like "0190b1f2-7c3e-7a41-9d2b-5f0e8c1a2b3c".
+func UUIDv7() string {
+	ms, hi, lo := uuidGen.next()
+
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[0:8], uint64(ms)<<16|0x7000|uint64(hi))
+	binary.BigEndian.PutUint64(b[8:16], 0x8000000000000000|lo)
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:36], b[10:16])
+	return string(s[:])
+}
+
+// ULID returns a 26-character ULID like "01J2RZ5X3E8Q4V7M9N2B6C1D0F".
+func ULID() string {
+	ms, hi, lo := ulidGen.next()
+
+	// 128 bits are encoded as 26 base32 characters, the first one holding only 3 bits.
+	hi |= uint64(ms) << 16
+	var s [26]byte
+	for i := 25; i >= 0; i-- {
+		s[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// ULIDTime returns the creation time encoded in a ULID.
+func ULIDTime(id string) (time.Time, bool) {
+	if len(id) != 26 {
+		return time.Time{}, false
+	}
+	var ms int64
+	for i := 0; i < 10; i++ {
+		n := strings.IndexByte(crockford, id[i])
+		if n < 0 || i == 0 && n > 7 {
+			return time.Time{}, false
+		}
+		ms = ms<<5 | int64(n)
+	}
+	return time.UnixMilli(ms), true
+}
+
+// Short returns a random base62 ID of ShortLength characters.
+func Short() string {
+	var b [ShortLength]byte
+	randomBytes(b[:])
+	for i := range b {
+		// 62*4 = 248, so rejecting larger bytes keeps the distribution uniform.
+		for b[i] >= 248 {
+			var r [1]byte
+			randomBytes(r[:])
+			b[i] = r[0]
+		}
+		b[i] = base62[b[i]%62]
+	}
+	return string(b[:])
+}
+
+// generator yields a millisecond timestamp and hiBits+loBits of entropy. Within the same
+// millisecond the entropy is incremented instead of regenerated, keeping IDs ordered.
+type generator struct {
+	hiBits, loBits uint
+	now            func() time.Time
+
+	mu     sync.Mutex
+	lastMs int64
+	hi, lo uint64
+}
+
+func (g *generator) next() (ms int64, hi, lo uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	hiMask := uint64(1)<<g.hiBits - 1
+	loMask := uint64(1)<<g.loBits - 1
+	if g.loBits == 64 {
+		loMask = ^uint64(0)
+	}
+
+	ms = g.now().UnixMilli()
+	if ms <= g.lastMs {
+		ms = g.lastMs
+		g.lo = (g.lo + 1) & loMask
+		if g.lo == 0 {
+			g.hi = (g.hi + 1) & hiMask
+			if g.hi == 0 {
+				// The entropy is exhausted, borrow the next millisecond.
+				ms++
+			}
+		}
+		g.lastMs = ms
+		return ms, g.hi, g.lo
+	}
+
+	var b [16]byte
+	randomBytes(b[:])
+	g.lastMs = ms
+	g.hi = binary.BigEndian.Uint64(b[0:8]) & hiMask
+	g.lo = binary.BigEndian.Uint64(b[8:16]) & loMask
+	return ms, g.hi, g.lo
+}
+
+func randomBytes(b []byte) {
+	_, err := rand.Read(b)
+	if err != nil {
+		panic("ids: crypto/rand failed: " + err.Error())
+	}
+}
@@ -0,0 +1,99 @@
+package ids
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDv7(t *testing.T) {
+	// when
+	id := UUIDv7()
+
+	// then
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+}
+
+func TestULID(t *testing.T) {
+	// given
+	before := time.Now().Truncate(time.Millisecond)
+
+	// when
+	id := ULID()
+
+	// then
+	assert.Regexp(t, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`), id)
+	created, ok := ULIDTime(id)
+	assert.True(t, ok)
+	assert.False(t, created.Before(before))
+	assert.WithinDuration(t, time.Now(), created, time.Second)
+}
+
+func TestULIDTimeRejectsInvalid(t *testing.T) {
+	for _, id := range []string{"", "01J2RZ5X3E", "81J2RZ5X3E8Q4V7M9N2B6C1D0F", "01J2RZ5X3U8Q4V7M9N2B6C1D0F"} {
+		_, ok := ULIDTime(id)
+		assert.False(t, ok, id)
+	}
+}
+
+func TestIDsAreSorted(t *testing.T) {
+	for name, gen := range map[string]func() string{"uuid": UUIDv7, "ulid": ULID} {
+		// given
+		ids := make([]string, 10000)
+
+		// when
+		for i := range ids {
+			ids[i] = gen()
+		}
+
+		// then
+		assert.True(t, sort.StringsAreSorted(ids), name)
+		assert.Len(t, unique(ids), len(ids), name)
+	}
+}
+
+func TestGeneratorWithinSameMillisecond(t *testing.T) {
+	// given
+	now := time.UnixMilli(1_700_000_000_000)
+	g := generator{hiBits: 12, loBits: 62, now: func() time.Time { return now }}
+	ms, _, _ := g.next()
+	g.hi, g.lo = 0, 1<<62-1
+
+	// when
+	ms2, hi2, lo2 := g.next()
+	g.hi = 1<<12 - 1
+	g.lo = 1<<62 - 1
+	ms3, hi3, lo3 := g.next()
+
+	// then
+	assert.Equal(t, now.UnixMilli(), ms)
+	assert.Equal(t, []any{ms, uint64(1), uint64(0)}, []any{ms2, hi2, lo2})
+	assert.Equal(t, []any{ms + 1, uint64(0), uint64(0)}, []any{ms3, hi3, lo3})
+}
+
+func TestShort(t *testing.T) {
+	// given
+	ids := make([]string, 1000)
+
+	// when
+	for i := range ids {
+		ids[i] = Short()
+	}
+
+	// then
+	for _, id := range ids {
+		assert.Regexp(t, regexp.MustCompile(`^[0-9A-Za-z]{12}$`), id)
+	}
+	assert.Len(t, unique(ids), len(ids))
+}
+
+func unique(ids []string) map[string]bool {
+	set := map[string]bool{}
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
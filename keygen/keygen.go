@@ -0,0 +1,66 @@
+// Package keygen generates cryptographically secure keys, tokens and API keys using crypto/rand.
+package keygen
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	Alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	URLSafe      = Alphanumeric + "-_"
+	Hex          = "0123456789abcdef"
+)
+
+// APIKeyLength is the length of the random part of keys from GenerateAPIKey, ~190 bits of entropy.
+const APIKeyLength = 32
+
+// GenerateKey returns bits/8 random bytes, e.g. GenerateKey(256) for an AES-256 key.
+func GenerateKey(bits int) ([]byte, error) {
+	if bits <= 0 || bits%8 != 0 {
+		return nil, fmt.Errorf("key size must be a positive multiple of 8 bits, got: %d", bits)
+	}
+
+	key := make([]byte, bits/8)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GenerateToken returns a random string of length characters drawn uniformly from alphabet.
+func GenerateToken(length int, alphabet string) (string, error) {
+	if length <= 0 {
+		return "", fmt.Errorf("token length must be positive, got: %d", length)
+	}
+	if len(alphabet) < 2 {
+		return "", errors.New("alphabet must contain at least 2 characters")
+	}
+
+	max := big.NewInt(int64(len(alphabet)))
+	token := make([]byte, length)
+	for i := range token {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		token[i] = alphabet[n.Int64()]
+	}
+	return string(token), nil
+}
+
+// GenerateAPIKey returns a key like "tb_4fK...", where the prefix identifies the issuing service
+// and makes leaked keys easy to find by secret scanners. An empty prefix returns just the random part.
+func GenerateAPIKey(prefix string) (string, error) {
+	token, err := GenerateToken(APIKeyLength, Alphanumeric)
+	if err != nil {
+		return "", err
+	}
+	if prefix == "" {
+		return token, nil
+	}
+	return prefix + "_" + token, nil
+}
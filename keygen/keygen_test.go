@@ -0,0 +1,49 @@
+package keygen
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateKey(t *testing.T) {
+	key, err := GenerateKey(256)
+	assert.NoError(t, err)
+	assert.Len(t, key, 32)
+
+	other, err := GenerateKey(256)
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	_, err = GenerateKey(100)
+	assert.Error(t, err)
+	_, err = GenerateKey(0)
+	assert.Error(t, err)
+}
+
+func TestGenerateToken(t *testing.T) {
+	token, err := GenerateToken(1000, Hex)
+	assert.NoError(t, err)
+	assert.Len(t, token, 1000)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]+$`), token)
+	for _, c := range Hex {
+		assert.True(t, strings.ContainsRune(token, c), "every character should appear in a long token")
+	}
+
+	_, err = GenerateToken(0, Hex)
+	assert.Error(t, err)
+	_, err = GenerateToken(10, "a")
+	assert.Error(t, err)
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, err := GenerateAPIKey("tb")
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^tb_[0-9A-Za-z]{32}$`), key)
+
+	key, err = GenerateAPIKey("")
+	assert.NoError(t, err)
+	assert.Len(t, key, APIKeyLength)
+}
@@ -0,0 +1,128 @@
+// Package logging configures slog loggers consistently across services.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/denis-kilchichakov/toolbox/system"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type Options struct {
+	// Format is FormatText or FormatJSON.
+	Format string
+	Level  slog.Level
+	// Service and Version are added to every record; Version defaults to system.Version().
+	Service string
+	Version string
+	// Output defaults to os.Stderr.
+	Output io.Writer
+}
+
+// OptionsFromEnv reads LOG_FORMAT (text or json, default text) and LOG_LEVEL (debug, info, warn
+// or error, default info).
+func OptionsFromEnv(service string) (Options, error) {
+	opts := Options{
+		Format:  FormatText,
+		Level:   slog.LevelInfo,
+		Service: service,
+	}
+
+	if format, ok := os.LookupEnv("LOG_FORMAT"); ok {
+		opts.Format = strings.ToLower(format)
+	}
+	if level, ok := os.LookupEnv("LOG_LEVEL"); ok {
+		err := opts.Level.UnmarshalText([]byte(level))
+		if err != nil {
+			return opts, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+		}
+	}
+	return opts, nil
+}
+
+// New returns a logger configured according to opts.
+func New(opts Options) (*slog.Logger, error) {
+	output := opts.Output
+	if output == nil {
+		output = os.Stderr
+	}
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level}
+
+	var handler slog.Handler
+	switch opts.Format {
+	case FormatText, "":
+		handler = slog.NewTextHandler(output, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(output, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unknown log format: %s", opts.Format)
+	}
+
+	version := opts.Version
+	if version == "" {
+		version = system.Version()
+	}
+	attrs := []slog.Attr{slog.String("version", version)}
+	if opts.Service != "" {
+		attrs = append([]slog.Attr{slog.String("service", opts.Service)}, attrs...)
+	}
+	return slog.New(handler.WithAttrs(attrs)), nil
+}
+
+// Setup configures the default logger from the environment, see OptionsFromEnv. Output of the
+// log package goes to it too.
+func Setup(service string) (*slog.Logger, error) {
+	opts, err := OptionsFromEnv(service)
+	if err != nil {
+		return nil, err
+	}
+	logger, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+// ErrorHook wraps a handler, additionally passing records at error level and above to hook,
+// e.g. to forward them to an alerting channel. The record doesn't include attributes added with
+// Logger.With. hook must not log through the same handler.
+func ErrorHook(next slog.Handler, hook func(ctx context.Context, record slog.Record)) slog.Handler {
+	return &errorHookHandler{next: next, hook: hook}
+}
+
+type errorHookHandler struct {
+	next slog.Handler
+	hook func(ctx context.Context, record slog.Record)
+}
+
+func (h *errorHookHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelError || h.next.Enabled(ctx, level)
+}
+
+func (h *errorHookHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelError {
+		h.hook(ctx, record.Clone())
+	}
+	if !h.next.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *errorHookHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &errorHookHandler{next: h.next.WithAttrs(attrs), hook: h.hook}
+}
+
+func (h *errorHookHandler) WithGroup(name string) slog.Handler {
+	return &errorHookHandler{next: h.next.WithGroup(name), hook: h.hook}
+}
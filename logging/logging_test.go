@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsFromEnv(t *testing.T) {
+	// given
+	t.Setenv("LOG_FORMAT", "JSON")
+	t.Setenv("LOG_LEVEL", "debug")
+
+	// when
+	opts, err := OptionsFromEnv("bot")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, Options{Format: FormatJSON, Level: slog.LevelDebug, Service: "bot"}, opts)
+
+	t.Setenv("LOG_LEVEL", "loud")
+	_, err = OptionsFromEnv("bot")
+	assert.ErrorContains(t, err, "invalid LOG_LEVEL")
+}
+
+func TestNew_JSON(t *testing.T) {
+	// given
+	var buf bytes.Buffer
+	logger, err := New(Options{Format: FormatJSON, Level: slog.LevelInfo, Service: "bot", Version: "1.2.3", Output: &buf})
+	assert.NoError(t, err)
+
+	// when
+	logger.Debug("hidden")
+	logger.Info("started", "port", 8080)
+
+	// then
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "started", record["msg"])
+	assert.Equal(t, "bot", record["service"])
+	assert.Equal(t, "1.2.3", record["version"])
+	assert.Equal(t, float64(8080), record["port"])
+}
+
+func TestNew_Text(t *testing.T) {
+	// given
+	var buf bytes.Buffer
+	logger, err := New(Options{Output: &buf})
+	assert.NoError(t, err)
+
+	// when
+	logger.Info("started")
+
+	// then
+	assert.Contains(t, buf.String(), "msg=started version=dev")
+
+	_, err = New(Options{Format: "xml"})
+	assert.Error(t, err)
+}
+
+func TestErrorHook(t *testing.T) {
+	// given
+	var buf bytes.Buffer
+	var hooked []string
+	next := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+	logger := slog.New(ErrorHook(next, func(ctx context.Context, record slog.Record) {
+		hooked = append(hooked, record.Message)
+	})).With("chat", 42)
+
+	// when
+	logger.Info("ignored")
+	logger.Warn("warning")
+	logger.Error("failed")
+
+	// then
+	assert.Equal(t, []string{"failed"}, hooked)
+	assert.Contains(t, buf.String(), "msg=warning chat=42")
+	assert.Contains(t, buf.String(), "msg=failed chat=42")
+	assert.NotContains(t, buf.String(), "ignored")
+}
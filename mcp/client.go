@@ -0,0 +1,174 @@
+// Package mcp is a Model Context Protocol client for calling tools of MCP servers over stdio or
+// HTTP.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+const ProtocolVersion = "2025-03-26"
+
+// Transport delivers JSON-RPC messages to a server.
+type Transport interface {
+	// Call sends a request and waits for the response with the same ID.
+	Call(ctx context.Context, req *Message) (*Message, error)
+	// Notify sends a notification, which has no response.
+	Notify(ctx context.Context, n *Message) error
+	Close() error
+}
+
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type InitializeResult struct {
+	ProtocolVersion string          `json:"protocolVersion"`
+	ServerInfo      Implementation  `json:"serverInfo"`
+	Capabilities    json.RawMessage `json:"capabilities"`
+	Instructions    string          `json:"instructions,omitempty"`
+}
+
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// Content is an item of a tool result: "text" with Text, or "image"/"audio" with base64 Data.
+type Content struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	// IsError reports that the tool failed; Content describes the failure.
+	IsError bool `json:"isError,omitempty"`
+}
+
+// Text joins text content items with newlines.
+func (r *CallToolResult) Text() string {
+	var parts []string
+	for _, c := range r.Content {
+		if c.Type == "text" {
+			parts = append(parts, c.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+type Client struct {
+	transport Transport
+	info      Implementation
+	lastID    atomic.Int64
+}
+
+// NewClient returns a client identifying itself as info. Call Initialize before other methods.
+func NewClient(transport Transport, info Implementation) *Client {
+	return &Client{
+		transport: transport,
+		info:      info,
+	}
+}
+
+// Initialize performs the protocol handshake.
+func (c *Client) Initialize(ctx context.Context) (*InitializeResult, error) {
+	var result InitializeResult
+	err := c.call(ctx, "initialize", map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      c.info,
+	}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("mcp initialize failed: %w", err)
+	}
+
+	n, err := newNotification("notifications/initialized", nil)
+	if err != nil {
+		return nil, err
+	}
+	err = c.transport.Notify(ctx, n)
+	if err != nil {
+		return nil, fmt.Errorf("mcp initialize failed: %w", err)
+	}
+	return &result, nil
+}
+
+// ListTools returns all tools of the server, following pagination.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	tools := []Tool{}
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor,omitempty"`
+		}
+		err := c.call(ctx, "tools/list", params, &page)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool invokes a tool. A tool failure is reported in the result with IsError, not as an error.
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]any) (*CallToolResult, error) {
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	var result CallToolResult
+	err := c.call(ctx, "tools/call", map[string]any{
+		"name":      name,
+		"arguments": arguments,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, "ping", nil, nil)
+}
+
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	req, err := newRequest(c.lastID.Add(1), method, params)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.transport.Call(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if len(resp.Result) == 0 {
+		return errors.New("mcp response has no result")
+	}
+	return json.Unmarshal(resp.Result, result)
+}
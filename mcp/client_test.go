@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fakeServerEnv = "MCP_FAKE_SERVER"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(fakeServerEnv) {
+	case "1":
+		serveFake(os.Stdin, os.Stdout)
+		os.Exit(0)
+	case "hang":
+		// ignores stdin being closed, like a stuck server
+		select {}
+	}
+	os.Exit(m.Run())
+}
+
+// handleFake implements a tiny MCP server with "echo" and "fail" tools listed on two pages.
+func handleFake(req *Message) *Message {
+	if !req.isRequest() {
+		return nil
+	}
+	resp := &Message{JSONRPC: jsonrpcVersion, ID: req.ID}
+	var result any
+	switch req.Method {
+	case "initialize":
+		result = map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"serverInfo":      map[string]any{"name": "fake", "version": "1.0"},
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+		}
+	case "ping":
+		result = map[string]any{}
+	case "tools/list":
+		var params struct {
+			Cursor string `json:"cursor"`
+		}
+		json.Unmarshal(req.Params, &params)
+		if params.Cursor == "" {
+			result = map[string]any{
+				"tools":      []map[string]any{{"name": "echo", "inputSchema": map[string]any{"type": "object"}}},
+				"nextCursor": "page2",
+			}
+		} else {
+			result = map[string]any{
+				"tools": []map[string]any{{"name": "fail", "inputSchema": map[string]any{"type": "object"}}},
+			}
+		}
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		json.Unmarshal(req.Params, &params)
+		switch params.Name {
+		case "echo":
+			result = map[string]any{"content": []map[string]any{{"type": "text", "text": params.Arguments["text"]}}}
+		case "fail":
+			result = map[string]any{"content": []map[string]any{{"type": "text", "text": "tool failed"}}, "isError": true}
+		default:
+			resp.Error = &RPCError{Code: CodeInvalidParams, Message: "unknown tool: " + params.Name}
+			return resp
+		}
+	default:
+		resp.Error = &RPCError{Code: CodeMethodNotFound, Message: "method not found"}
+		return resp
+	}
+	resp.Result, _ = json.Marshal(result)
+	return resp
+}
+
+func serveFake(r io.Reader, w io.Writer) {
+	scanner := bufio.NewScanner(r)
+	encoder := json.NewEncoder(w)
+	for scanner.Scan() {
+		var req Message
+		if json.Unmarshal(scanner.Bytes(), &req) != nil {
+			continue
+		}
+		if req.Method == "tools/list" {
+			// a server-initiated request interleaved with responses
+			encoder.Encode(&Message{JSONRPC: jsonrpcVersion, ID: json.RawMessage(`"srv-1"`), Method: "ping"})
+		}
+		if resp := handleFake(&req); resp != nil {
+			encoder.Encode(resp)
+		}
+	}
+}
+
+func pipeTransport(t *testing.T) *streamTransport {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	go func() {
+		serveFake(serverR, serverW)
+		serverW.Close()
+	}()
+	transport := newStreamTransport(clientR, clientW)
+	t.Cleanup(func() { transport.Close() })
+	return transport
+}
+
+func testClient(t *testing.T, transport Transport) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := NewClient(transport, Implementation{Name: "toolbox", Version: "test"})
+
+	info, err := client.Initialize(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "fake", info.ServerInfo.Name)
+	assert.Equal(t, ProtocolVersion, info.ProtocolVersion)
+
+	assert.NoError(t, client.Ping(ctx))
+
+	tools, err := client.ListTools(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tools, 2)
+	assert.Equal(t, "echo", tools[0].Name)
+	assert.Equal(t, "fail", tools[1].Name)
+	assert.JSONEq(t, `{"type": "object"}`, string(tools[0].InputSchema))
+
+	result, err := client.CallTool(ctx, "echo", map[string]any{"text": "hello"})
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "hello", result.Text())
+
+	result, err = client.CallTool(ctx, "fail", nil)
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "tool failed", result.Text())
+
+	_, err = client.CallTool(ctx, "missing", nil)
+	var rpcErr *RPCError
+	assert.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, CodeInvalidParams, rpcErr.Code)
+
+	assert.NoError(t, client.Close())
+}
+
+func TestClient_Stream(t *testing.T) {
+	testClient(t, pipeTransport(t))
+}
+
+func TestCallToolResult_Text(t *testing.T) {
+	result := CallToolResult{Content: []Content{
+		{Type: "text", Text: "first"},
+		{Type: "image", Data: "aGk=", MimeType: "image/png"},
+		{Type: "text", Text: "second"},
+	}}
+
+	assert.Equal(t, "first\nsecond", result.Text())
+}
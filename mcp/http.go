@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const sessionIDHeader = "Mcp-Session-Id"
+
+// HTTPTransport implements the streamable HTTP transport: every message is POSTed to the endpoint,
+// and the server responds either with JSON or with a stream of server-sent events.
+type HTTPTransport struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+// NewHTTPTransport returns a transport for the server endpoint, e.g. "http://localhost:8000/mcp".
+// client may be nil to use http.DefaultClient; pass an httpclient for timeouts and retries.
+func NewHTTPTransport(url string, client *http.Client) *HTTPTransport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPTransport{
+		url:    url,
+		client: client,
+	}
+}
+
+func (t *HTTPTransport) Call(ctx context.Context, req *Message) (*Message, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		return readEventStream(resp.Body, req.ID)
+	}
+
+	var msg Message
+	err = json.NewDecoder(resp.Body).Decode(&msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode mcp response: %w", err)
+	}
+	return &msg, nil
+}
+
+func (t *HTTPTransport) Notify(ctx context.Context, n *Message) error {
+	resp, err := t.post(ctx, n)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// Close terminates the session, if the server assigned one.
+func (t *HTTPTransport) Close() error {
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.sessionID = ""
+	t.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(sessionIDHeader, sessionID)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (t *HTTPTransport) post(ctx context.Context, msg *Message) (*http.Response, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.mu.Lock()
+	if t.sessionID != "" {
+		req.Header.Set(sessionIDHeader, t.sessionID)
+	}
+	t.mu.Unlock()
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, fmt.Errorf("mcp server responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if sessionID := resp.Header.Get(sessionIDHeader); sessionID != "" {
+		t.mu.Lock()
+		t.sessionID = sessionID
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// readEventStream returns the response with the given ID from a stream of server-sent events,
+// skipping notifications sent before it. Data lines of an event are joined with newlines, and an
+// event not followed by a blank line is still dispatched at the end of the stream.
+func readEventStream(r io.Reader, id json.RawMessage) (*Message, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var data strings.Builder
+	hasData := false
+	dispatch := func() (*Message, error) {
+		var msg Message
+		err := json.Unmarshal([]byte(data.String()), &msg)
+		data.Reset()
+		hasData = false
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode mcp event: %w", err)
+		}
+		if msg.isResponse() && bytes.Equal(msg.ID, id) {
+			return &msg, nil
+		}
+		return nil, nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(value, " "))
+			hasData = true
+			continue
+		}
+		if line != "" || !hasData {
+			continue
+		}
+
+		msg, err := dispatch()
+		if msg != nil || err != nil {
+			return msg, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if hasData {
+		msg, err := dispatch()
+		if msg != nil || err != nil {
+			return msg, err
+		}
+	}
+	return nil, fmt.Errorf("mcp event stream ended without a response")
+}
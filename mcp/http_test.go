@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeHTTPServer(t *testing.T, sse bool) (*httptest.Server, *[]string) {
+	var sessions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			sessions = append(sessions, "deleted "+r.Header.Get(sessionIDHeader))
+			return
+		}
+		var req Message
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Method == "initialize" {
+			w.Header().Set(sessionIDHeader, "session-1")
+		} else {
+			sessions = append(sessions, r.Header.Get(sessionIDHeader))
+		}
+
+		resp := handleFake(&req)
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		data, _ := json.Marshal(resp)
+		if !sse {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(data)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	}))
+	t.Cleanup(server.Close)
+	return server, &sessions
+}
+
+func TestHTTPTransport_JSON(t *testing.T) {
+	// given
+	server, sessions := fakeHTTPServer(t, false)
+
+	// when
+	testClient(t, NewHTTPTransport(server.URL, nil))
+
+	// then
+	assert.Equal(t, "session-1", (*sessions)[0])
+	assert.Equal(t, "deleted session-1", (*sessions)[len(*sessions)-1])
+}
+
+func TestHTTPTransport_SSE(t *testing.T) {
+	server, _ := fakeHTTPServer(t, true)
+
+	testClient(t, NewHTTPTransport(server.URL, nil))
+}
+
+func TestReadEventStream(t *testing.T) {
+	stream := "data: {\"jsonrpc\":\"2.0\",\"id\":2,\"result\":{}}\n\n"
+
+	_, err := readEventStream(strings.NewReader(stream), json.RawMessage("1"))
+	assert.ErrorContains(t, err, "ended without a response")
+
+	msg, err := readEventStream(strings.NewReader(stream), json.RawMessage("2"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, "{}", string(msg.Result))
+}
+
+func TestReadEventStream_MultilineData(t *testing.T) {
+	// given
+	stream := "event: message\n" +
+		"data: {\"jsonrpc\":\"2.0\",\n" +
+		"data: \"id\":2,\n" +
+		"data: \"result\":{\"lines\":[1,\n" +
+		"data: 2]}}\n\n"
+
+	// when
+	msg, err := readEventStream(strings.NewReader(stream), json.RawMessage("2"))
+
+	// then
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"lines":[1,2]}`, string(msg.Result))
+
+	// lines are separated, so "1" and "2" don't merge into 12
+	split := "data: {\"jsonrpc\":\"2.0\",\"id\":2,\"result\":{\"n\":1\ndata: 2}}\n\n"
+	_, err = readEventStream(strings.NewReader(split), json.RawMessage("2"))
+	assert.ErrorContains(t, err, "failed to decode mcp event")
+}
+
+func TestReadEventStream_LastEventWithoutBlankLine(t *testing.T) {
+	// given
+	stream := "data: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n" +
+		"data: {\"jsonrpc\":\"2.0\",\"id\":2,\"result\":{}}"
+
+	// when
+	msg, err := readEventStream(strings.NewReader(stream), json.RawMessage("2"))
+
+	// then
+	assert.NoError(t, err)
+	assert.JSONEq(t, "{}", string(msg.Result))
+}
+
+func TestHTTPTransport_ErrorStatus(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	client := NewClient(NewHTTPTransport(server.URL, nil), Implementation{Name: "toolbox"})
+
+	// when
+	_, err := client.Initialize(context.Background())
+
+	// then
+	assert.ErrorContains(t, err, "status 401: unauthorized")
+}
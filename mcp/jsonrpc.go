@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const jsonrpcVersion = "2.0"
+
+// JSON-RPC error codes used by MCP servers.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Message is a JSON-RPC request, notification (no ID) or response, as exchanged by transports.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+func (m *Message) isResponse() bool {
+	return m.Method == "" && len(m.ID) > 0
+}
+
+func (m *Message) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0
+}
+
+// RPCError is an error returned by the server.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+func newRequest(id int64, method string, params any) (*Message, error) {
+	msg, err := newNotification(method, params)
+	if err != nil {
+		return nil, err
+	}
+	msg.ID = json.RawMessage(fmt.Sprint(id))
+	return msg, nil
+}
+
+func newNotification(method string, params any) (*Message, error) {
+	msg := &Message{JSONRPC: jsonrpcVersion, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		msg.Params = data
+	}
+	return msg, nil
+}
@@ -0,0 +1,218 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+var ErrTransportClosed = errors.New("mcp transport is closed")
+
+// StdioCloseTimeout is how long Close waits for the server to exit after closing its stdin
+// before killing it.
+var StdioCloseTimeout = 5 * time.Second
+
+// StdioTransport talks to a server subprocess through newline-delimited JSON on its stdin and stdout.
+type StdioTransport struct {
+	cmd *exec.Cmd
+	*streamTransport
+}
+
+// NewStdioTransport starts cmd and connects to its stdin and stdout. The server's stderr is
+// inherited unless cmd.Stderr is set. Close stops the server.
+func NewStdioTransport(cmd *exec.Cmd) (*StdioTransport, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start mcp server: %w", err)
+	}
+
+	return &StdioTransport{
+		cmd:             cmd,
+		streamTransport: newStreamTransport(stdout, stdin),
+	}, nil
+}
+
+// Close closes the server's stdin, which asks it to exit, and waits for it. A server still running
+// after StdioCloseTimeout is killed.
+func (t *StdioTransport) Close() error {
+	err := t.streamTransport.Close()
+	waited := make(chan error, 1)
+	go func() {
+		waited <- t.cmd.Wait()
+	}()
+
+	var waitErr error
+	timer := time.NewTimer(StdioCloseTimeout)
+	defer timer.Stop()
+	select {
+	case waitErr = <-waited:
+	case <-timer.C:
+		log.Println("MCP server did not exit after stdin was closed, killing it")
+		t.cmd.Process.Kill()
+		waitErr = <-waited
+	}
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		// killed or exited non-zero after stdin was closed, nothing to report
+		waitErr = nil
+	}
+	return errors.Join(err, waitErr)
+}
+
+// streamTransport multiplexes requests over a pair of streams, matching responses by ID.
+type streamTransport struct {
+	w       io.WriteCloser
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan *Message
+	err     error
+	done    chan struct{}
+}
+
+func newStreamTransport(r io.Reader, w io.WriteCloser) *streamTransport {
+	t := &streamTransport{
+		w:       w,
+		pending: map[string]chan *Message{},
+		done:    make(chan struct{}),
+	}
+	go t.read(r)
+	return t
+}
+
+func (t *streamTransport) Call(ctx context.Context, req *Message) (*Message, error) {
+	id := string(req.ID)
+	ch := make(chan *Message, 1)
+
+	t.mu.Lock()
+	if t.err != nil {
+		t.mu.Unlock()
+		return nil, t.err
+	}
+	t.pending[id] = ch
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+	}()
+
+	err := t.write(req)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-t.done:
+		return nil, t.closedErr()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (t *streamTransport) Notify(ctx context.Context, n *Message) error {
+	return t.write(n)
+}
+
+func (t *streamTransport) Close() error {
+	t.fail(ErrTransportClosed)
+	return t.w.Close()
+}
+
+func (t *streamTransport) write(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.w.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write mcp message: %w", err)
+	}
+	return nil
+}
+
+func (t *streamTransport) read(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var msg Message
+		err := json.Unmarshal(scanner.Bytes(), &msg)
+		if err != nil {
+			log.Println("Skipping malformed mcp message: ", err)
+			continue
+		}
+
+		switch {
+		case msg.isResponse():
+			t.mu.Lock()
+			ch, ok := t.pending[string(msg.ID)]
+			t.mu.Unlock()
+			if ok {
+				// the buffer holds one response, a duplicate ID must not block reading
+				select {
+				case ch <- &msg:
+				default:
+					log.Println("Dropping duplicate mcp response: ", string(msg.ID))
+				}
+			}
+		case msg.isRequest():
+			go t.respond(&msg)
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	t.fail(fmt.Errorf("mcp server closed the connection: %w", err))
+}
+
+// respond answers requests initiated by the server: pings are acknowledged, anything else is
+// not supported by this client.
+func (t *streamTransport) respond(req *Message) {
+	resp := &Message{JSONRPC: jsonrpcVersion, ID: req.ID}
+	if req.Method == "ping" {
+		resp.Result = json.RawMessage("{}")
+	} else {
+		resp.Error = &RPCError{Code: CodeMethodNotFound, Message: "method not supported: " + req.Method}
+	}
+	err := t.write(resp)
+	if err != nil {
+		log.Println("Failed to respond to mcp server request: ", err)
+	}
+}
+
+func (t *streamTransport) fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err == nil {
+		t.err = err
+		close(t.done)
+	}
+}
+
+func (t *streamTransport) closedErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdioTransport(t *testing.T) {
+	// given
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), fakeServerEnv+"=1")
+
+	// when
+	transport, err := NewStdioTransport(cmd)
+
+	// then
+	assert.NoError(t, err)
+	testClient(t, transport)
+}
+
+func TestStdioTransport_CloseKillsStuckServer(t *testing.T) {
+	// given
+	closeTimeout := StdioCloseTimeout
+	StdioCloseTimeout = 100 * time.Millisecond
+	defer func() { StdioCloseTimeout = closeTimeout }()
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), fakeServerEnv+"=hang")
+	transport, err := NewStdioTransport(cmd)
+	if err != nil {
+		t.Fatalf("NewStdioTransport failed: %v", err)
+	}
+
+	// when
+	closed := make(chan error, 1)
+	go func() {
+		closed <- transport.Close()
+	}()
+
+	// then
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("close did not kill the stuck server")
+	}
+}
+
+func TestStreamTransport_DuplicateResponse(t *testing.T) {
+	// given
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	transport := newStreamTransport(clientR, clientW)
+	defer transport.Close()
+	transport.mu.Lock()
+	transport.pending["1"] = make(chan *Message, 1)
+	transport.mu.Unlock()
+
+	// when
+	go func() {
+		io.WriteString(serverW, `{"jsonrpc":"2.0","id":1,"result":{}}`+"\n")
+		io.WriteString(serverW, `{"jsonrpc":"2.0","id":1,"result":{}}`+"\n")
+		io.WriteString(serverW, `{"jsonrpc":"2.0","id":7,"method":"ping"}`+"\n")
+	}()
+
+	// then
+	lines := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(serverR)
+		if scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	select {
+	case line := <-lines:
+		assert.Contains(t, line, `"id":7`)
+	case <-time.After(time.Second):
+		t.Fatal("reading stopped after a duplicate response")
+	}
+}
+
+func TestStreamTransport_ServerGone(t *testing.T) {
+	// given
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	go io.Copy(io.Discard, serverR)
+	transport := newStreamTransport(clientR, clientW)
+	req, _ := newRequest(1, "ping", nil)
+	errs := make(chan error, 1)
+
+	// when
+	go func() {
+		_, err := transport.Call(context.Background(), req)
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	serverW.Close()
+
+	// then
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(time.Second):
+		t.Fatal("call did not fail when the server went away")
+	}
+	_, err := transport.Call(context.Background(), req)
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Calls counts calls of a subsystem and their durations by operation and status ("ok" or "error").
+// It implements Observer and sqldb.Metrics.
+type Calls struct {
+	total    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// Calls registers <namespace>_<subsystem>_calls_total and <namespace>_<subsystem>_call_duration_seconds,
+// e.g. Calls("sql") for sqldb or Calls("llm") for an LLM client.
+func (r *Registry) Calls(subsystem string) *Calls {
+	c := &Calls{
+		total: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: r.namespace,
+			Subsystem: subsystem,
+			Name:      "calls_total",
+			Help:      "Number of calls by operation and status.",
+		}, []string{"operation", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: r.namespace,
+			Subsystem: subsystem,
+			Name:      "call_duration_seconds",
+			Help:      "Call duration by operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+	}
+	r.reg.MustRegister(c.total, c.duration)
+	return c
+}
+
+func (c *Calls) Observe(operation string, duration time.Duration, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	c.total.WithLabelValues(operation, status).Inc()
+	c.duration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// QueryDone implements sqldb.Metrics.
+func (c *Calls) QueryDone(operation string, duration time.Duration, err error) {
+	c.Observe(operation, duration, err)
+}
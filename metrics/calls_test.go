@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ sqldb.Metrics = (*Calls)(nil)
+var _ Observer = (*Calls)(nil)
+
+func TestCalls(t *testing.T) {
+	// given
+	r := New("bot")
+	calls := r.Calls("sql")
+
+	// when
+	calls.Observe("select", 10*time.Millisecond, nil)
+	calls.QueryDone("select", 20*time.Millisecond, nil)
+	calls.QueryDone("insert", time.Millisecond, errors.New("locked"))
+
+	// then
+	expected := `
+# HELP bot_sql_calls_total Number of calls by operation and status.
+# TYPE bot_sql_calls_total counter
+bot_sql_calls_total{operation="insert",status="error"} 1
+bot_sql_calls_total{operation="select",status="ok"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(r.Gatherer(), strings.NewReader(expected), "bot_sql_calls_total"))
+	count, err := testutil.GatherAndCount(r.Gatherer(), "bot_sql_call_duration_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestCalls_WithSqlDb(t *testing.T) {
+	// given
+	r := New("bot")
+	db, err := sqldb.InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMetrics(r.Calls("sql"))
+
+	// when
+	_, err = db.ExecCtx(context.Background(), "CREATE TABLE t (id INTEGER)")
+
+	// then
+	assert.NoError(t, err)
+	count, _ := testutil.GatherAndCount(r.Gatherer(), "bot_sql_calls_total")
+	assert.Equal(t, 1, count)
+}
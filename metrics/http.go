@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/httpserver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Server returns an httpserver serving the metrics at /metrics.
+func (r *Registry) Server(addr string) *httpserver.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.Handler())
+	return httpserver.New(mux, httpserver.DefaultOptions(addr))
+}
+
+// HTTPMiddleware returns middleware counting requests by method and status code and observing
+// their durations. Paths are not used as labels to keep cardinality bounded. All handlers wrapped
+// with middleware of the same registry share the metrics.
+func (r *Registry) HTTPMiddleware() httpserver.Middleware {
+	r.httpOnce.Do(func() {
+		r.httpTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: r.namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by method and status code.",
+		}, []string{"method", "code"})
+		r.httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: r.namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request duration by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"})
+		r.reg.MustRegister(r.httpTotal, r.httpDuration)
+	})
+	total, duration := r.httpTotal, r.httpDuration
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			rec := httpserver.NewStatusRecorder(w)
+			next.ServeHTTP(rec, req)
+			total.WithLabelValues(req.Method, strconv.Itoa(rec.Status)).Inc()
+			duration.WithLabelValues(req.Method).Observe(time.Since(start).Seconds())
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	// given
+	r := New("bot")
+	handler := r.HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/missing" {
+			http.NotFound(w, req)
+		}
+	}))
+
+	// when
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	// then
+	expected := `
+# HELP bot_http_requests_total Number of HTTP requests by method and status code.
+# TYPE bot_http_requests_total counter
+bot_http_requests_total{code="200",method="GET"} 1
+bot_http_requests_total{code="404",method="GET"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(r.Gatherer(), strings.NewReader(expected), "bot_http_requests_total"))
+}
+
+func TestHTTPMiddleware_SeveralHandlers(t *testing.T) {
+	// given
+	r := New("bot")
+	ok := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	api := r.HTTPMiddleware()(ok)
+	admin := r.HTTPMiddleware()(ok)
+
+	// when
+	api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	admin.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// then
+	expected := `
+# HELP bot_http_requests_total Number of HTTP requests by method and status code.
+# TYPE bot_http_requests_total counter
+bot_http_requests_total{code="200",method="GET"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(r.Gatherer(), strings.NewReader(expected), "bot_http_requests_total"))
+}
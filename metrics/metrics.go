@@ -0,0 +1,97 @@
+// Package metrics wraps Prometheus registration and defines instrumentation shared by toolbox
+// packages: an Observer for calls to external services and databases, and HTTP middleware.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Observer records outcomes of calls: LLM requests, Telegram API calls, SQL queries.
+// operation should have low cardinality, e.g. a method name rather than a URL.
+type Observer interface {
+	Observe(operation string, duration time.Duration, err error)
+}
+
+// Registry holds metrics of a service, all named with its namespace prefix.
+type Registry struct {
+	namespace string
+	reg       *prometheus.Registry
+
+	// httpOnce registers the collectors shared by all handlers wrapped with HTTPMiddleware
+	httpOnce     sync.Once
+	httpTotal    *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+}
+
+// New returns a registry with Go runtime and process collectors registered.
+func New(namespace string) *Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return &Registry{
+		namespace: namespace,
+		reg:       reg,
+	}
+}
+
+// Registerer allows registering custom collectors.
+func (r *Registry) Registerer() prometheus.Registerer {
+	return r.reg
+}
+
+func (r *Registry) Gatherer() prometheus.Gatherer {
+	return r.reg
+}
+
+// Handler serves the metrics in the Prometheus exposition format, usually mounted at /metrics.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.reg, promhttp.HandlerOpts{Registry: r.reg})
+}
+
+// Counter registers a counter vector named <namespace>_<name>.
+func (r *Registry) Counter(name, help string, labels ...string) *prometheus.CounterVec {
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: r.namespace,
+		Name:      name,
+		Help:      help,
+	}, labels)
+	r.reg.MustRegister(c)
+	return c
+}
+
+// Gauge registers a gauge vector named <namespace>_<name>.
+func (r *Registry) Gauge(name, help string, labels ...string) *prometheus.GaugeVec {
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: r.namespace,
+		Name:      name,
+		Help:      help,
+	}, labels)
+	r.reg.MustRegister(g)
+	return g
+}
+
+// Histogram registers a histogram vector named <namespace>_<name> with default buckets.
+func (r *Registry) Histogram(name, help string, labels ...string) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: r.namespace,
+		Name:      name,
+		Help:      help,
+		Buckets:   prometheus.DefBuckets,
+	}, labels)
+	r.reg.MustRegister(h)
+	return h
+}
+
+// RegisterDBStats exports connection pool stats of db, labeled with db_name.
+func (r *Registry) RegisterDBStats(name string, db *sql.DB) {
+	r.reg.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
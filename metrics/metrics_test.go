@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestRegistry_Handler(t *testing.T) {
+	// given
+	r := New("bot")
+	r.Counter("updates_total", "Updates received.", "type").WithLabelValues("message").Add(2)
+	r.Gauge("chats", "Active chats.").WithLabelValues().Set(3)
+	r.Histogram("reply_seconds", "Reply latency.").WithLabelValues().Observe(0.2)
+
+	// when
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// then
+	body := rec.Body.String()
+	assert.Contains(t, body, `bot_updates_total{type="message"} 2`)
+	assert.Contains(t, body, "bot_chats 3")
+	assert.Contains(t, body, "bot_reply_seconds_count 1")
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestRegistry_RegisterDBStats(t *testing.T) {
+	// given
+	r := New("bot")
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer db.Close()
+
+	// when
+	r.RegisterDBStats("main", db)
+
+	// then
+	count, err := testutil.GatherAndCount(r.Gatherer(), "go_sql_max_open_connections")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestRegistry_Server(t *testing.T) {
+	// given
+	s := New("bot").Server("127.0.0.1:0")
+	assert.NoError(t, s.Start())
+	defer s.Stop(context.Background())
+
+	// when
+	resp, err := http.Get("http://" + s.Addr() + "/metrics")
+
+	// then
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.True(t, strings.Contains(string(body), "go_goroutines"))
+}
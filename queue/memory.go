@@ -0,0 +1,86 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryBackend keeps jobs in memory; they are lost on restart. Buried jobs are kept for inspection.
+type MemoryBackend struct {
+	capacity int
+
+	mu      sync.Mutex
+	lastID  int64
+	pending []memoryJob
+	dead    []Job
+}
+
+type memoryJob struct {
+	job   Job
+	runAt time.Time
+}
+
+// NewMemoryBackend returns a backend holding at most capacity pending jobs, zero means unbounded.
+func NewMemoryBackend(capacity int) *MemoryBackend {
+	return &MemoryBackend{capacity: capacity}
+}
+
+func (m *MemoryBackend) Enqueue(ctx context.Context, payload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.capacity > 0 && len(m.pending) >= m.capacity {
+		return ErrQueueFull
+	}
+	m.lastID++
+	m.pending = append(m.pending, memoryJob{job: Job{ID: m.lastID, Payload: payload}, runAt: time.Now()})
+	return nil
+}
+
+func (m *MemoryBackend) Next(ctx context.Context) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for i, pending := range m.pending {
+		if !pending.runAt.After(now) {
+			m.pending = append(m.pending[:i], m.pending[i+1:]...)
+			job := pending.job
+			return &job, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MemoryBackend) Complete(ctx context.Context, job *Job) error {
+	return nil
+}
+
+func (m *MemoryBackend) Retry(ctx context.Context, job *Job, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending = append(m.pending, memoryJob{job: *job, runAt: at})
+	return nil
+}
+
+func (m *MemoryBackend) Bury(ctx context.Context, job *Job, cause error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dead = append(m.dead, *job)
+	return nil
+}
+
+// Len returns the number of pending jobs, including scheduled retries.
+func (m *MemoryBackend) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pending)
+}
+
+// Dead returns buried jobs.
+func (m *MemoryBackend) Dead() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Job(nil), m.dead...)
+}
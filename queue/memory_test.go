@@ -0,0 +1,35 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	// given
+	ctx := context.Background()
+	m := NewMemoryBackend(2)
+
+	// when
+	assert.NoError(t, m.Enqueue(ctx, []byte("a")))
+	assert.NoError(t, m.Enqueue(ctx, []byte("b")))
+	assert.ErrorIs(t, m.Enqueue(ctx, []byte("c")), ErrQueueFull)
+
+	// then
+	a, _ := m.Next(ctx)
+	assert.Equal(t, "a", string(a.Payload))
+	assert.NoError(t, m.Retry(ctx, a, time.Now().Add(time.Hour)))
+
+	b, _ := m.Next(ctx)
+	assert.Equal(t, "b", string(b.Payload))
+	assert.NoError(t, m.Bury(ctx, b, errors.New("boom")))
+
+	none, _ := m.Next(ctx)
+	assert.Nil(t, none, "a is scheduled in the future")
+	assert.Equal(t, 1, m.Len())
+	assert.Equal(t, []Job{*b}, m.Dead())
+}
@@ -0,0 +1,175 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+type Handler func(ctx context.Context, job *Job) error
+
+type Options struct {
+	Workers int
+	// MaxAttempts is the number of attempts before a job is buried.
+	MaxAttempts int
+	// Backoff returns the delay before retrying a job that failed attempts times.
+	Backoff func(attempts int) time.Duration
+	// PollInterval is how often idle workers check the backend for due jobs.
+	PollInterval time.Duration
+	// DeadLetter is called after a job is buried, e.g. to alert.
+	DeadLetter func(ctx context.Context, job *Job, cause error)
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Workers:      4,
+		MaxAttempts:  5,
+		Backoff:      ExponentialBackoff(time.Second, 5*time.Minute),
+		PollInterval: time.Second,
+	}
+}
+
+// ExponentialBackoff doubles the delay after every failed attempt, starting with base.
+func ExponentialBackoff(base, max time.Duration) func(attempts int) time.Duration {
+	return func(attempts int) time.Duration {
+		delay := base
+		for i := 1; i < attempts && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+// Pool processes jobs from a backend with a fixed number of workers.
+type Pool struct {
+	backend Backend
+	handler Handler
+	opts    Options
+	wake    chan struct{}
+}
+
+func New(backend Backend, handler Handler, opts Options) *Pool {
+	return &Pool{
+		backend: backend,
+		handler: handler,
+		opts:    opts,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Enqueue adds a job and wakes an idle worker.
+func (p *Pool) Enqueue(ctx context.Context, payload []byte) error {
+	err := p.backend.Enqueue(ctx, payload)
+	if err != nil {
+		return err
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run processes jobs until ctx is done, then waits for in-flight jobs and returns ctx.Err().
+// Handlers get ctx, so they're canceled on shutdown; jobs interrupted this way are rescheduled
+// without counting the attempt.
+func (p *Pool) Run(ctx context.Context) error {
+	if p.opts.Workers <= 0 {
+		return fmt.Errorf("number of workers must be positive: %d", p.opts.Workers)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (p *Pool) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := p.backend.Next(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Println("Failed to fetch next job: ", err)
+		}
+		if job == nil {
+			p.idle(ctx)
+			continue
+		}
+		p.process(ctx, job)
+	}
+}
+
+func (p *Pool) idle(ctx context.Context) {
+	timer := time.NewTimer(p.opts.PollInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-p.wake:
+	case <-timer.C:
+	}
+}
+
+func (p *Pool) process(ctx context.Context, job *Job) {
+	err := p.handle(ctx, job)
+	// bookkeeping must complete even if the pool is shutting down
+	bgCtx := context.WithoutCancel(ctx)
+
+	if err == nil {
+		err = p.backend.Complete(bgCtx, job)
+		if err != nil {
+			log.Printf("Failed to complete job %d: %v", job.ID, err)
+		}
+		return
+	}
+
+	if ctx.Err() != nil {
+		err = p.backend.Retry(bgCtx, job, time.Now())
+		if err != nil {
+			log.Printf("Failed to reschedule job %d: %v", job.ID, err)
+		}
+		return
+	}
+
+	job.Attempts++
+	if job.Attempts >= p.opts.MaxAttempts {
+		log.Printf("Job %d failed %d times, burying: %v", job.ID, job.Attempts, err)
+		buryErr := p.backend.Bury(bgCtx, job, err)
+		if buryErr != nil {
+			log.Printf("Failed to bury job %d: %v", job.ID, buryErr)
+		}
+		if p.opts.DeadLetter != nil {
+			p.opts.DeadLetter(bgCtx, job, err)
+		}
+		return
+	}
+
+	var delay time.Duration
+	if p.opts.Backoff != nil {
+		delay = p.opts.Backoff(job.Attempts)
+	}
+	log.Printf("Job %d failed, retrying in %s: %v", job.ID, delay, err)
+	err = p.backend.Retry(bgCtx, job, time.Now().Add(delay))
+	if err != nil {
+		log.Printf("Failed to reschedule job %d: %v", job.ID, err)
+	}
+}
+
+func (p *Pool) handle(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return p.handler(ctx, job)
+}
@@ -0,0 +1,144 @@
+package queue
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func testOptions() Options {
+	return Options{
+		Workers:      2,
+		MaxAttempts:  3,
+		Backoff:      func(attempts int) time.Duration { return time.Millisecond },
+		PollInterval: 5 * time.Millisecond,
+	}
+}
+
+func runPool(t *testing.T, p *Pool, until func() bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !until() {
+		if time.Now().After(deadline) {
+			t.Error("condition was not met in time")
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestPool_ProcessesJobs(t *testing.T) {
+	// given
+	backend := NewMemoryBackend(0)
+	var mu sync.Mutex
+	processed := []string{}
+	p := New(backend, func(ctx context.Context, job *Job) error {
+		mu.Lock()
+		defer mu.Unlock()
+		processed = append(processed, string(job.Payload))
+		return nil
+	}, testOptions())
+
+	// when
+	for _, payload := range []string{"a", "b", "c"} {
+		assert.NoError(t, p.Enqueue(context.Background(), []byte(payload)))
+	}
+	runPool(t, p, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(processed) == 3
+	})
+
+	// then
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, processed)
+	assert.Zero(t, backend.Len())
+}
+
+func TestPool_RetriesAndDeadLetters(t *testing.T) {
+	// given
+	captureLog(t)
+	backend := NewMemoryBackend(0)
+	var calls atomic.Int32
+	var dead atomic.Pointer[Job]
+	opts := testOptions()
+	opts.DeadLetter = func(ctx context.Context, job *Job, cause error) {
+		dead.Store(job)
+	}
+	p := New(backend, func(ctx context.Context, job *Job) error {
+		if calls.Add(1) == 2 {
+			panic("kaboom")
+		}
+		return errors.New("boom")
+	}, opts)
+
+	// when
+	p.Enqueue(context.Background(), []byte("job"))
+	runPool(t, p, func() bool { return dead.Load() != nil })
+
+	// then
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, 3, dead.Load().Attempts)
+	assert.Len(t, backend.Dead(), 1)
+	assert.Zero(t, backend.Len())
+}
+
+func TestPool_ShutdownReschedulesInterruptedJob(t *testing.T) {
+	// given
+	backend := NewMemoryBackend(0)
+	started := make(chan struct{})
+	p := New(backend, func(ctx context.Context, job *Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, testOptions())
+	p.Enqueue(context.Background(), []byte("job"))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// when
+	go func() {
+		<-started
+		cancel()
+	}()
+	err := p.Run(ctx)
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	job, _ := backend.Next(context.Background())
+	assert.Equal(t, 0, job.Attempts)
+}
+
+func TestPool_InvalidWorkers(t *testing.T) {
+	p := New(NewMemoryBackend(0), nil, Options{})
+
+	assert.Error(t, p.Run(context.Background()))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, 2*time.Second, backoff(2))
+	assert.Equal(t, 4*time.Second, backoff(3))
+	assert.Equal(t, 5*time.Second, backoff(4))
+	assert.Equal(t, 5*time.Second, backoff(100))
+}
@@ -0,0 +1,28 @@
+// Package queue runs jobs on a bounded pool of workers with retries and dead-letter handling,
+// backed by memory or, for durability, by an sqldb database.
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrQueueFull = errors.New("queue is full")
+
+type Job struct {
+	ID      int64
+	Payload []byte
+	// Attempts is the number of failed attempts so far.
+	Attempts int
+}
+
+// Backend stores jobs. Next claims a due job so other workers don't get it, or returns nil when
+// there is none; a claimed job is then completed, rescheduled or buried by the pool.
+type Backend interface {
+	Enqueue(ctx context.Context, payload []byte) error
+	Next(ctx context.Context) (*Job, error)
+	Complete(ctx context.Context, job *Job) error
+	Retry(ctx context.Context, job *Job, at time.Time) error
+	Bury(ctx context.Context, job *Job, cause error) error
+}
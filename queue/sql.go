@@ -0,0 +1,152 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+)
+
+const sqlInitialScript = `
+CREATE TABLE IF NOT EXISTS queue_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    queue TEXT NOT NULL,
+    payload BLOB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at BIGINT NOT NULL,
+    dead INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL
+);
+CREATE INDEX IF NOT EXISTS queue_jobs_due ON queue_jobs (queue, dead, run_at);
+`
+
+const sqlInitialScriptMySQL = `
+CREATE TABLE IF NOT EXISTS queue_jobs (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    queue VARCHAR(255) NOT NULL,
+    payload LONGBLOB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    run_at BIGINT NOT NULL,
+    dead TINYINT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    PRIMARY KEY (id),
+    INDEX queue_jobs_due (queue, dead, run_at)
+);
+`
+
+// DefaultLease is how long a claimed job is hidden from other workers.
+const DefaultLease = 5 * time.Minute
+
+// SqlBackend is a durable backend keeping jobs in the queue_jobs table, shared by named queues.
+// A claimed job is leased: if the worker dies before completing it, the job is delivered again
+// once Lease expires, so handlers should be idempotent.
+type SqlBackend struct {
+	db    *sqldb.SqlDb
+	queue string
+	Lease time.Duration
+	now   func() time.Time
+}
+
+// NewSqlBackend creates the queue_jobs table if needed and returns a backend for the named queue.
+func NewSqlBackend(ctx context.Context, db *sqldb.SqlDb, queue string) (*SqlBackend, error) {
+	script := sqlInitialScript
+	if db.Dialect() == sqldb.DialectMySQL {
+		script = sqlInitialScriptMySQL
+	}
+	_, err := db.ExecCtx(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SqlBackend{
+		db:    db,
+		queue: queue,
+		Lease: DefaultLease,
+		now:   time.Now,
+	}, nil
+}
+
+func (s *SqlBackend) Enqueue(ctx context.Context, payload []byte) error {
+	_, err := s.db.ExecCtx(ctx,
+		"INSERT INTO queue_jobs (queue, payload, run_at) VALUES ($1, $2, $3)",
+		s.queue, payload, s.now().UnixMilli(),
+	)
+	return err
+}
+
+// Next claims the oldest due job. It returns nil if there is none or another worker claimed it first.
+func (s *SqlBackend) Next(ctx context.Context) (*Job, error) {
+	now := s.now()
+	job := &Job{}
+	var runAt int64
+	err := s.db.QueryRowCtx(ctx,
+		"SELECT id, payload, attempts, run_at FROM queue_jobs WHERE queue = $1 AND dead = 0 AND run_at <= $2 ORDER BY run_at, id LIMIT 1",
+		s.queue, now.UnixMilli(),
+	).Scan(&job.ID, &job.Payload, &job.Attempts, &runAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.ExecCtx(ctx,
+		"UPDATE queue_jobs SET run_at = $1 WHERE id = $2 AND run_at = $3",
+		now.Add(s.Lease).UnixMilli(), job.ID, runAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if claimed == 0 {
+		return nil, nil
+	}
+	return job, nil
+}
+
+func (s *SqlBackend) Complete(ctx context.Context, job *Job) error {
+	_, err := s.db.ExecCtx(ctx, "DELETE FROM queue_jobs WHERE id = $1", job.ID)
+	return err
+}
+
+func (s *SqlBackend) Retry(ctx context.Context, job *Job, at time.Time) error {
+	_, err := s.db.ExecCtx(ctx,
+		"UPDATE queue_jobs SET attempts = $1, run_at = $2 WHERE id = $3",
+		job.Attempts, at.UnixMilli(), job.ID,
+	)
+	return err
+}
+
+func (s *SqlBackend) Bury(ctx context.Context, job *Job, cause error) error {
+	_, err := s.db.ExecCtx(ctx,
+		"UPDATE queue_jobs SET attempts = $1, dead = 1, last_error = $2 WHERE id = $3",
+		job.Attempts, cause.Error(), job.ID,
+	)
+	return err
+}
+
+// Dead returns buried jobs of the queue, oldest first.
+func (s *SqlBackend) Dead(ctx context.Context) ([]Job, error) {
+	rows, err := s.db.QueryCtx(ctx,
+		"SELECT id, payload, attempts FROM queue_jobs WHERE queue = $1 AND dead = 1 ORDER BY id",
+		s.queue,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []Job{}
+	for rows.Next() {
+		var job Job
+		err = rows.Scan(&job.ID, &job.Payload, &job.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
@@ -0,0 +1,106 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupSqlBackend(t *testing.T, queue string) (*SqlBackend, *sqldb.SqlDb) {
+	db, err := sqldb.InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	backend, err := NewSqlBackend(context.Background(), db, queue)
+	if err != nil {
+		t.Fatalf("NewSqlBackend failed: %v", err)
+	}
+	return backend, db
+}
+
+func TestSqlBackend(t *testing.T) {
+	// given
+	ctx := context.Background()
+	s, db := setupSqlBackend(t, "llm")
+	other, err := NewSqlBackend(ctx, db, "other")
+	assert.NoError(t, err)
+
+	// when
+	assert.NoError(t, s.Enqueue(ctx, []byte("a")))
+	assert.NoError(t, s.Enqueue(ctx, []byte("b")))
+	assert.NoError(t, other.Enqueue(ctx, []byte("c")))
+
+	// then
+	a, err := s.Next(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", string(a.Payload))
+
+	b, _ := s.Next(ctx)
+	assert.Equal(t, "b", string(b.Payload))
+
+	none, _ := s.Next(ctx)
+	assert.Nil(t, none, "a and b are leased")
+
+	a.Attempts = 1
+	assert.NoError(t, s.Retry(ctx, a, time.Now().Add(-time.Second)))
+	a, _ = s.Next(ctx)
+	assert.Equal(t, 1, a.Attempts)
+	assert.NoError(t, s.Complete(ctx, a))
+
+	b.Attempts = 5
+	assert.NoError(t, s.Bury(ctx, b, errors.New("boom")))
+	dead, err := s.Dead(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []Job{*b}, dead)
+
+	none, _ = s.Next(ctx)
+	assert.Nil(t, none)
+	c, _ := other.Next(ctx)
+	assert.Equal(t, "c", string(c.Payload))
+}
+
+func TestSqlBackend_LeaseExpires(t *testing.T) {
+	// given
+	ctx := context.Background()
+	s, _ := setupSqlBackend(t, "llm")
+	s.Enqueue(ctx, []byte("a"))
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	first, _ := s.Next(ctx)
+
+	// when
+	s.now = func() time.Time { return now.Add(DefaultLease + time.Second) }
+	second, err := s.Next(ctx)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+}
+
+func TestSqlBackend_WithPool(t *testing.T) {
+	// given
+	ctx := context.Background()
+	s, _ := setupSqlBackend(t, "llm")
+	processed := make(chan string, 2)
+	p := New(s, func(ctx context.Context, job *Job) error {
+		processed <- string(job.Payload)
+		return nil
+	}, testOptions())
+
+	// when
+	p.Enqueue(ctx, []byte("a"))
+	p.Enqueue(ctx, []byte("b"))
+	runPool(t, p, func() bool { return len(processed) == 2 })
+
+	// then
+	assert.ElementsMatch(t, []string{"a", "b"}, []string{<-processed, <-processed})
+	none, _ := s.Next(ctx)
+	assert.Nil(t, none)
+}
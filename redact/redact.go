@@ -0,0 +1,129 @@
+// Package redact scrubs known secrets from strings and log records.
+package redact
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const Replacement = "[REDACTED]"
+
+// TelegramBotToken matches Telegram bot API tokens, e.g. 123456789:AAH...
+var TelegramBotToken = regexp.MustCompile(`\d{6,12}:[A-Za-z0-9_-]{30,}`)
+
+// Redactor replaces registered secret values and patterns with Replacement. It's safe for concurrent use.
+type Redactor struct {
+	mu       sync.RWMutex
+	values   []string
+	patterns []*regexp.Regexp
+}
+
+func New() *Redactor {
+	return &Redactor{}
+}
+
+// AddValue registers a literal secret, e.g. an API key loaded from configuration. Empty values are ignored.
+func (r *Redactor) AddValue(value string) {
+	if value == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values = append(r.values, value)
+	// longer values first, so a secret containing another one is replaced as a whole
+	sort.SliceStable(r.values, func(i, j int) bool {
+		return len(r.values[i]) > len(r.values[j])
+	})
+}
+
+func (r *Redactor) AddPattern(pattern *regexp.Regexp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns = append(r.patterns, pattern)
+}
+
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, value := range r.values {
+		s = strings.ReplaceAll(s, value, Replacement)
+	}
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllString(s, Replacement)
+	}
+	return s
+}
+
+// Handler wraps an slog.Handler, redacting the message and attribute values of every record.
+func (r *Redactor) Handler(next slog.Handler) slog.Handler {
+	return &handler{
+		redactor: r,
+		next:     next,
+	}
+}
+
+type handler struct {
+	redactor *Redactor
+	next     slog.Handler
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redactor.Redact(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redactAttr(attr)
+	}
+	return &handler{
+		redactor: h.redactor,
+		next:     h.next.WithAttrs(redacted),
+	}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{
+		redactor: h.redactor,
+		next:     h.next.WithGroup(name),
+	}
+}
+
+func (h *handler) redactAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, h.redactor.Redact(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, len(group))
+		for i, a := range group {
+			redacted[i] = h.redactAttr(a)
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		s := fmt.Sprint(value.Any())
+		if r := h.redactor.Redact(s); r != s {
+			return slog.String(attr.Key, r)
+		}
+		return slog.Attr{Key: attr.Key, Value: value}
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
@@ -0,0 +1,57 @@
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	// given
+	r := New()
+	r.AddValue("api-key")
+	r.AddValue("api-key-extended")
+	r.AddValue("")
+	r.AddPattern(TelegramBotToken)
+	r.AddPattern(regexp.MustCompile(`password=\S+`))
+
+	// when
+	redacted := r.Redact("calling https://api.telegram.org/bot123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw1/getUpdates with api-key-extended, api-key and password=hunter2")
+
+	// then
+	assert.Equal(t, "calling https://api.telegram.org/bot[REDACTED]/getUpdates with [REDACTED], [REDACTED] and [REDACTED]", redacted)
+}
+
+func TestHandler(t *testing.T) {
+	// given
+	r := New()
+	r.AddValue("s3cr3t")
+	var out bytes.Buffer
+	logger := slog.New(r.Handler(slog.NewTextHandler(&out, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	// when
+	logger.With("token", "s3cr3t").
+		WithGroup("request").
+		Info("sending s3cr3t",
+			"url", "https://example.com/?key=s3cr3t",
+			"err", errors.New("auth failed for s3cr3t"),
+			slog.Group("headers", "authorization", "Bearer s3cr3t"),
+			"status", 401,
+		)
+
+	// then
+	assert.NotContains(t, out.String(), "s3cr3t")
+	assert.Contains(t, out.String(), "request.status=401")
+	assert.Contains(t, out.String(), `request.headers.authorization="Bearer [REDACTED]"`)
+}
@@ -0,0 +1,133 @@
+// Package retry retries operations with exponential backoff and jitter.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+type Policy struct {
+	// MaxAttempts limits the number of attempts, zero means no limit (then set MaxElapsed).
+	MaxAttempts int
+	// InitialDelay is the delay after the first failure; every next one is Multiplier times longer,
+	// up to MaxDelay.
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// Jitter randomizes every delay by up to this fraction in both directions, e.g. 0.2 for ±20%.
+	Jitter float64
+	// MaxElapsed stops retrying when the next attempt would start after this time since the first one.
+	MaxElapsed time.Duration
+	// Retryable classifies errors; nil means every error is retryable. Errors wrapped with
+	// Permanent are never retried.
+	Retryable func(err error) bool
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:  5,
+		InitialDelay: 500 * time.Millisecond,
+		MaxDelay:     30 * time.Second,
+		Multiplier:   2,
+		Jitter:       0.2,
+		MaxElapsed:   2 * time.Minute,
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not worth retrying. Do returns the unwrapped err.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// RetryAfter is implemented by errors carrying a server-provided delay, e.g. from a 429 response.
+// A positive value replaces the computed backoff delay for the next attempt.
+type RetryAfter interface {
+	RetryAfter() time.Duration
+}
+
+// Do calls fn until it succeeds, returns a non-retryable error or the policy is exhausted,
+// and returns the last error. If ctx is done while waiting, ctx.Err() is joined with it.
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, policy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue is like Do for functions returning a value.
+func DoValue[T any](ctx context.Context, policy Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	delay := policy.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		value, err := fn(ctx)
+		if err == nil {
+			return value, nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return value, permanent.err
+		}
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			return value, err
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return value, err
+		}
+
+		wait := policy.jittered(delay)
+		var retryAfter RetryAfter
+		if errors.As(err, &retryAfter) && retryAfter.RetryAfter() > 0 {
+			wait = retryAfter.RetryAfter()
+		}
+		if policy.MaxElapsed > 0 && time.Since(start)+wait > policy.MaxElapsed {
+			return value, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return value, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+
+		delay = policy.next(delay)
+	}
+}
+
+func (p Policy) next(delay time.Duration) time.Duration {
+	if p.Multiplier > 0 {
+		delay = time.Duration(float64(delay) * p.Multiplier)
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+func (p Policy) jittered(delay time.Duration) time.Duration {
+	if p.Jitter <= 0 || delay <= 0 {
+		return delay
+	}
+	factor := 1 + p.Jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(delay) * factor)
+}
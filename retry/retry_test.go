@@ -0,0 +1,169 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rateLimitedError struct {
+	after time.Duration
+}
+
+func (e rateLimitedError) Error() string {
+	return "rate limited"
+}
+
+func (e rateLimitedError) RetryAfter() time.Duration {
+	return e.after
+}
+
+func testPolicy() Policy {
+	return Policy{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     5 * time.Millisecond,
+		Multiplier:   2,
+	}
+}
+
+func TestDo_SucceedsAfterRetries(t *testing.T) {
+	// given
+	attempts := 0
+
+	// when
+	err := Do(context.Background(), testPolicy(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestDo_ReturnsLastError(t *testing.T) {
+	// given
+	attempts := 0
+	lastErr := errors.New("attempt 3")
+
+	// when
+	err := Do(context.Background(), testPolicy(), func(ctx context.Context) error {
+		attempts++
+		if attempts == 3 {
+			return lastErr
+		}
+		return errors.New("temporary")
+	})
+
+	// then
+	assert.Equal(t, lastErr, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestDo_NonRetryable(t *testing.T) {
+	// given
+	badRequest := errors.New("bad request")
+	policy := testPolicy()
+	policy.Retryable = func(err error) bool { return !errors.Is(err, badRequest) }
+	attempts := 0
+
+	// when
+	err := Do(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return badRequest
+	})
+
+	// then
+	assert.Equal(t, badRequest, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestDo_Permanent(t *testing.T) {
+	// given
+	cause := errors.New("unauthorized")
+	attempts := 0
+
+	// when
+	err := Do(context.Background(), testPolicy(), func(ctx context.Context) error {
+		attempts++
+		return Permanent(cause)
+	})
+
+	// then
+	assert.Equal(t, cause, err)
+	assert.Equal(t, 1, attempts)
+	assert.Nil(t, Permanent(nil))
+}
+
+func TestDo_ContextCanceled(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := testPolicy()
+	policy.InitialDelay = time.Hour
+	cause := errors.New("temporary")
+
+	// when
+	err := Do(ctx, policy, func(ctx context.Context) error {
+		cancel()
+		return cause
+	})
+
+	// then
+	assert.ErrorIs(t, err, cause)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDo_MaxElapsed(t *testing.T) {
+	// given
+	policy := Policy{InitialDelay: 10 * time.Millisecond, MaxElapsed: 25 * time.Millisecond}
+	attempts := 0
+
+	// when
+	err := Do(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return errors.New("temporary")
+	})
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts, "attempts at 0, 10 and 20ms; the next one would start after 25ms")
+}
+
+func TestDoValue_RetryAfter(t *testing.T) {
+	// given
+	policy := testPolicy()
+	policy.InitialDelay = time.Hour
+	attempts := 0
+
+	// when
+	value, err := DoValue(context.Background(), policy, func(ctx context.Context) (string, error) {
+		attempts++
+		if attempts == 1 {
+			return "", rateLimitedError{after: time.Millisecond}
+		}
+		return "ok", nil
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", value)
+}
+
+func TestPolicy_Delays(t *testing.T) {
+	p := Policy{InitialDelay: time.Second, MaxDelay: 3 * time.Second, Multiplier: 2, Jitter: 0.5}
+
+	assert.Equal(t, 2*time.Second, p.next(time.Second))
+	assert.Equal(t, 3*time.Second, p.next(2*time.Second))
+	for i := 0; i < 100; i++ {
+		d := p.jittered(time.Second)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, 1500*time.Millisecond)
+	}
+}
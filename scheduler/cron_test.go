@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	at := func(s string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		expr     string
+		after    string
+		expected string
+	}{
+		{"* * * * *", "2024-03-10 12:00", "2024-03-10 12:01"},
+		{"*/15 * * * *", "2024-03-10 12:07", "2024-03-10 12:15"},
+		{"0 9-18 * * 1-5", "2024-03-08 18:30", "2024-03-11 09:00"},
+		{"30 4 1,15 * *", "2024-03-02 00:00", "2024-03-15 04:30"},
+		{"0 0 29 2 *", "2024-03-01 00:00", "2028-02-29 00:00"},
+		{"0 12 13 * 5", "2024-03-10 00:00", "2024-03-13 12:00"},
+		{"0 0 * * 7", "2024-03-10 12:00", "2024-03-17 00:00"},
+		{"@daily", "2024-12-31 23:59", "2025-01-01 00:00"},
+		{"@hourly", "2024-03-10 12:00", "2024-03-10 13:00"},
+		{"5/20 * * * *", "2024-03-10 12:26", "2024-03-10 12:45"},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		assert.NoError(t, err, tt.expr)
+		assert.Equal(t, at(tt.expected), s.Next(at(tt.after)), tt.expr)
+	}
+}
+
+func TestCronSchedule_NeverMatches(t *testing.T) {
+	s := MustParseCron("0 0 31 2 *")
+
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "* * * 13 *"} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+	assert.Panics(t, func() { MustParseCron("bad") })
+}
+
+func TestEvery(t *testing.T) {
+	now := time.Now()
+
+	assert.Equal(t, now.Add(time.Minute), Every(time.Minute).Next(now))
+}
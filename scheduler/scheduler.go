@@ -0,0 +1,184 @@
+// Package scheduler runs jobs on cron expressions or fixed intervals.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+type Job func(ctx context.Context) error
+
+// MissedRunPolicy decides what happens with activations missed while the process was down
+// (requires a Store) or while the previous run of the job was still in progress.
+type MissedRunPolicy int
+
+const (
+	// SkipMissed waits for the next activation after now.
+	SkipMissed MissedRunPolicy = iota
+	// RunMissedOnce runs the job immediately, once regardless of how many activations were missed.
+	RunMissedOnce
+)
+
+type JobOptions struct {
+	MissedRuns MissedRunPolicy
+}
+
+// Store persists last run times, so missed runs can be detected across restarts.
+type Store interface {
+	LastRun(ctx context.Context, name string) (time.Time, bool, error)
+	SetLastRun(ctx context.Context, name string, t time.Time) error
+}
+
+type job struct {
+	name     string
+	schedule Schedule
+	fn       Job
+	opts     JobOptions
+}
+
+// Scheduler runs registered jobs until its context is done. Runs of the same job never overlap;
+// different jobs run concurrently.
+type Scheduler struct {
+	store Store
+	now   func() time.Time
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	running bool
+}
+
+// New returns a scheduler. store may be nil, then last run times are not persisted.
+func New(store Store) *Scheduler {
+	return &Scheduler{
+		store: store,
+		now:   time.Now,
+		jobs:  map[string]*job{},
+	}
+}
+
+// Add registers a job with SkipMissed policy, see AddWithOptions.
+func (s *Scheduler) Add(name string, schedule Schedule, fn Job) error {
+	return s.AddWithOptions(name, schedule, fn, JobOptions{})
+}
+
+// AddWithOptions registers a job under a unique name. Jobs must be added before Run.
+func (s *Scheduler) AddWithOptions(name string, schedule Schedule, fn Job, opts JobOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return errors.New("scheduler is already running")
+	}
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("job %s is already registered", name)
+	}
+	if i, ok := schedule.(interval); ok && i <= 0 {
+		return fmt.Errorf("job %s: interval must be positive, got %s", name, time.Duration(i))
+	}
+	s.jobs[name] = &job{name: name, schedule: schedule, fn: fn, opts: opts}
+	return nil
+}
+
+// Run runs jobs until ctx is done, then waits for running jobs to return and returns ctx.Err().
+// Job errors and panics are logged and don't affect other runs.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return errors.New("scheduler is already running")
+	}
+	s.running = true
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		go func(j *job) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+	return ctx.Err()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	next := s.firstRun(ctx, j)
+	for !next.IsZero() {
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.execute(ctx, j)
+		if ctx.Err() != nil {
+			return
+		}
+		next = j.following(next, s.now())
+	}
+}
+
+func (s *Scheduler) firstRun(ctx context.Context, j *job) time.Time {
+	now := s.now()
+	if s.store == nil {
+		return j.schedule.Next(now)
+	}
+
+	last, ok, err := s.store.LastRun(ctx, j.name)
+	if err != nil {
+		log.Printf("Failed to load last run of job %s: %v", j.name, err)
+		return j.schedule.Next(now)
+	}
+	if !ok {
+		return j.schedule.Next(now)
+	}
+	return j.following(last, now)
+}
+
+// following returns the activation after prev, applying the missed run policy if it's already past.
+func (j *job) following(prev, now time.Time) time.Time {
+	next := j.schedule.Next(prev)
+	if next.IsZero() || !next.Before(now) {
+		return next
+	}
+	if j.opts.MissedRuns == RunMissedOnce {
+		return now
+	}
+	return j.schedule.Next(now)
+}
+
+func (s *Scheduler) execute(ctx context.Context, j *job) {
+	start := s.now()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panicked: %v\n%s", j.name, r, debug.Stack())
+		}
+		if s.store != nil {
+			err := s.store.SetLastRun(context.WithoutCancel(ctx), j.name, start)
+			if err != nil {
+				log.Printf("Failed to save last run of job %s: %v", j.name, err)
+			}
+		}
+	}()
+
+	err := j.fn(ctx)
+	if err != nil {
+		log.Printf("Job %s failed: %v", j.name, err)
+	}
+}
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStore struct {
+	mu   sync.Mutex
+	runs map[string]time.Time
+}
+
+func (m *memoryStore) LastRun(ctx context.Context, name string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.runs[name]
+	return t, ok, nil
+}
+
+func (m *memoryStore) SetLastRun(ctx context.Context, name string, t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[name] = t
+	return nil
+}
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestScheduler_Run(t *testing.T) {
+	// given
+	out := captureLog(t)
+	s := New(nil)
+	var ok, failing, panicking atomic.Int32
+	assert.NoError(t, s.Add("ok", Every(10*time.Millisecond), func(ctx context.Context) error {
+		ok.Add(1)
+		return nil
+	}))
+	assert.NoError(t, s.Add("failing", Every(10*time.Millisecond), func(ctx context.Context) error {
+		failing.Add(1)
+		return errors.New("boom")
+	}))
+	assert.NoError(t, s.Add("panicking", Every(10*time.Millisecond), func(ctx context.Context) error {
+		panicking.Add(1)
+		panic("kaboom")
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+
+	// when
+	err := s.Run(ctx)
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, ok.Load(), int32(3))
+	assert.GreaterOrEqual(t, failing.Load(), int32(3))
+	assert.GreaterOrEqual(t, panicking.Load(), int32(3))
+	assert.Contains(t, out.String(), "Job failing failed: boom")
+	assert.Contains(t, out.String(), "Job panicking panicked: kaboom")
+}
+
+func TestScheduler_Add(t *testing.T) {
+	s := New(nil)
+	noop := func(ctx context.Context) error { return nil }
+
+	assert.NoError(t, s.Add("job", Every(time.Hour), noop))
+	assert.Error(t, s.Add("job", Every(time.Hour), noop))
+}
+
+func TestScheduler_AddNonPositiveInterval(t *testing.T) {
+	s := New(nil)
+	noop := func(ctx context.Context) error { return nil }
+
+	assert.Error(t, s.Add("zero", Every(0), noop))
+	assert.Error(t, s.AddWithOptions("negative", Every(-time.Second), noop, JobOptions{}))
+}
+
+func TestScheduler_MissedRuns(t *testing.T) {
+	// given
+	store := &memoryStore{runs: map[string]time.Time{
+		"catch-up": time.Now().Add(-2 * time.Hour),
+		"skip":     time.Now().Add(-2 * time.Hour),
+	}}
+	s := New(store)
+	var catchUp, skip atomic.Int32
+	s.AddWithOptions("catch-up", Every(time.Hour), func(ctx context.Context) error {
+		catchUp.Add(1)
+		return nil
+	}, JobOptions{MissedRuns: RunMissedOnce})
+	s.Add("skip", Every(time.Hour), func(ctx context.Context) error {
+		skip.Add(1)
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	// when
+	s.Run(ctx)
+
+	// then
+	assert.Equal(t, int32(1), catchUp.Load())
+	assert.Equal(t, int32(0), skip.Load())
+	last, ok, _ := store.LastRun(ctx, "catch-up")
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now(), last, time.Second)
+}
+
+func TestJob_Following(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	skip := &job{schedule: Every(time.Minute)}
+	catchUp := &job{schedule: Every(time.Minute), opts: JobOptions{MissedRuns: RunMissedOnce}}
+
+	assert.Equal(t, now.Add(30*time.Second), skip.following(now.Add(-30*time.Second), now))
+	assert.Equal(t, now.Add(time.Minute), skip.following(now.Add(-5*time.Minute), now))
+	assert.Equal(t, now, catchUp.following(now.Add(-5*time.Minute), now))
+}
@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+)
+
+const sqlStoreInitialScript = `
+CREATE TABLE IF NOT EXISTS scheduler_runs (
+    name VARCHAR(255) NOT NULL,
+    last_run BIGINT NOT NULL,
+    PRIMARY KEY (name)
+);
+`
+
+// SqlStore keeps last run times in the scheduler_runs table.
+type SqlStore struct {
+	db *sqldb.SqlDb
+}
+
+// NewSqlStore creates the scheduler_runs table if needed and returns a store on top of it.
+func NewSqlStore(ctx context.Context, db *sqldb.SqlDb) (*SqlStore, error) {
+	_, err := db.ExecCtx(ctx, sqlStoreInitialScript)
+	if err != nil {
+		return nil, err
+	}
+	return &SqlStore{db: db}, nil
+}
+
+func (s *SqlStore) LastRun(ctx context.Context, name string) (time.Time, bool, error) {
+	var lastRun int64
+	err := s.db.QueryRowCtx(ctx, "SELECT last_run FROM scheduler_runs WHERE name = $1", name).Scan(&lastRun)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	} else if err != nil {
+		return time.Time{}, false, err
+	}
+	return time.UnixMilli(lastRun), true, nil
+}
+
+func (s *SqlStore) SetLastRun(ctx context.Context, name string, t time.Time) error {
+	_, err := s.db.ExecCtx(ctx, "REPLACE INTO scheduler_runs (name, last_run) VALUES ($1, $2)", name, t.UnixMilli())
+	return err
+}
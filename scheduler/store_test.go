@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqlStore(t *testing.T) {
+	// given
+	db, err := sqldb.InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	store, err := NewSqlStore(ctx, db)
+	if err != nil {
+		t.Fatalf("NewSqlStore failed: %v", err)
+	}
+	runAt := time.UnixMilli(time.Now().UnixMilli())
+
+	// when
+	_, found, err := store.LastRun(ctx, "job")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.SetLastRun(ctx, "job", runAt.Add(-time.Hour)))
+	assert.NoError(t, store.SetLastRun(ctx, "job", runAt))
+
+	// then
+	last, found, err := store.LastRun(ctx, "job")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.True(t, runAt.Equal(last))
+}
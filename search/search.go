@@ -0,0 +1,274 @@
+// Package search is a client of the Brave Search API.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+	"github.com/denis-kilchichakov/toolbox/httpclient"
+)
+
+const DefaultBaseURL = "https://api.search.brave.com/res/v1"
+
+type Options struct {
+	BaseURL string
+	// HTTPClient defaults to an httpclient without retries: rate limits are reported as
+	// *RateLimitError, which works with retry.Do.
+	HTTPClient *http.Client
+}
+
+type Client struct {
+	apiKey  string
+	baseURL string
+	http    *http.Client
+}
+
+type Query struct {
+	Query string
+	// Count is the number of results, the API allows up to 20.
+	Count  int
+	Offset int
+	// Country is a 2-letter country code, e.g. "us".
+	Country    string
+	SearchLang string
+	// SafeSearch is "off", "moderate" or "strict".
+	SafeSearch string
+	// Freshness is "pd", "pw", "pm", "py" (past day, week, month, year) or a date range
+	// "YYYY-MM-DDtoYYYY-MM-DD".
+	Freshness string
+}
+
+type Result struct {
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+	// Age is a human-readable age like "2 days ago", if known.
+	Age string `json:"age,omitempty"`
+}
+
+type NewsResult struct {
+	Result
+	Source string `json:"source,omitempty"`
+}
+
+type WebResponse struct {
+	Query   string
+	Results []Result
+}
+
+type NewsResponse struct {
+	Query   string
+	Results []NewsResult
+}
+
+// APIError is returned for unsuccessful responses other than rate limiting.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("brave search api error: status %d: %s", e.StatusCode, e.Body)
+}
+
+// Is matches errs.Unauthorized for 401 and 403 responses.
+func (e *APIError) Is(target error) bool {
+	return target == errs.Unauthorized && (e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden)
+}
+
+// RateLimitError is returned when the API responds with 429. It implements retry.RetryAfter.
+type RateLimitError struct {
+	// Limit and Remaining are per-window quotas from X-RateLimit headers, e.g. "1, 15000" for
+	// per-second and per-month windows.
+	Limit     string
+	Remaining string
+	Reset     time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("brave search rate limit exceeded, resets in %s", e.Reset)
+}
+
+func (e *RateLimitError) RetryAfter() time.Duration {
+	return e.Reset
+}
+
+// Is matches errs.RateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == errs.RateLimited
+}
+
+func New(apiKey string) (*Client, error) {
+	return NewWithOptions(apiKey, Options{})
+}
+
+func NewWithOptions(apiKey string, opts Options) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("brave search api key is empty")
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		var err error
+		client, err = httpclient.New(httpclient.Options{Timeout: 30 * time.Second})
+		if err != nil {
+			return nil, err
+		}
+	}
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    client,
+	}, nil
+}
+
+// Web searches the web.
+func (c *Client) Web(ctx context.Context, q Query) (*WebResponse, error) {
+	var body struct {
+		Query struct {
+			Original string `json:"original"`
+		} `json:"query"`
+		Web struct {
+			Results []Result `json:"results"`
+		} `json:"web"`
+	}
+	err := c.get(ctx, "/web/search", q, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	results := body.Web.Results
+	if results == nil {
+		results = []Result{}
+	}
+	return &WebResponse{Query: body.Query.Original, Results: results}, nil
+}
+
+// News searches news articles.
+func (c *Client) News(ctx context.Context, q Query) (*NewsResponse, error) {
+	var body struct {
+		Query struct {
+			Original string `json:"original"`
+		} `json:"query"`
+		Results []struct {
+			Result
+			MetaURL struct {
+				Hostname string `json:"hostname"`
+			} `json:"meta_url"`
+		} `json:"results"`
+	}
+	err := c.get(ctx, "/news/search", q, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]NewsResult, 0, len(body.Results))
+	for _, r := range body.Results {
+		results = append(results, NewsResult{Result: r.Result, Source: r.MetaURL.Hostname})
+	}
+	return &NewsResponse{Query: body.Query.Original, Results: results}, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, q Query, out any) error {
+	if strings.TrimSpace(q.Query) == "" {
+		return fmt.Errorf("search query is empty")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+q.values().Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Subscription-Token", c.apiKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{
+			Limit:     resp.Header.Get("X-RateLimit-Limit"),
+			Remaining: resp.Header.Get("X-RateLimit-Remaining"),
+			Reset:     rateLimitReset(resp.Header.Get("X-RateLimit-Remaining"), resp.Header.Get("X-RateLimit-Reset")),
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("failed to decode brave search response: %w", err)
+	}
+	return nil
+}
+
+func (q Query) values() url.Values {
+	v := url.Values{}
+	v.Set("q", q.Query)
+	if q.Count > 0 {
+		v.Set("count", strconv.Itoa(q.Count))
+	}
+	if q.Offset > 0 {
+		v.Set("offset", strconv.Itoa(q.Offset))
+	}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("country", q.Country)
+	set("search_lang", q.SearchLang)
+	set("safesearch", q.SafeSearch)
+	set("freshness", q.Freshness)
+	return v
+}
+
+// rateLimitReset parses X-RateLimit-Reset, seconds until each window resets, e.g. "1, 1419704",
+// and returns the longest reset among exhausted windows according to X-RateLimit-Remaining,
+// falling back to the first window.
+func rateLimitReset(remaining, reset string) time.Duration {
+	resets := splitInts(reset)
+	if len(resets) == 0 {
+		return 0
+	}
+
+	var longest int
+	for i, left := range splitInts(remaining) {
+		if left == 0 && i < len(resets) && resets[i] > longest {
+			longest = resets[i]
+		}
+	}
+	if longest == 0 {
+		longest = resets[0]
+	}
+	return time.Duration(longest) * time.Second
+}
+
+func splitInts(header string) []int {
+	var values []int
+	for _, part := range strings.Split(header, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil
+		}
+		values = append(values, n)
+	}
+	return values
+}
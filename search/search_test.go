@@ -0,0 +1,153 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewWithOptions("key", Options{BaseURL: server.URL + "/"})
+	if err != nil {
+		t.Fatalf("NewWithOptions failed: %v", err)
+	}
+	return client
+}
+
+func TestClient_Web(t *testing.T) {
+	// given
+	var request *http.Request
+	client := setupClient(t, func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		w.Write([]byte(`{
+			"query": {"original": "golang"},
+			"web": {"results": [
+				{"title": "The Go Programming Language", "url": "https://go.dev/", "description": "Go is ...", "age": "2 days ago"}
+			]}
+		}`))
+	})
+
+	// when
+	resp, err := client.Web(context.Background(), Query{Query: "golang", Count: 5, Country: "us", Freshness: "pw"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "/web/search", request.URL.Path)
+	assert.Equal(t, "count=5&country=us&freshness=pw&q=golang", request.URL.RawQuery)
+	assert.Equal(t, "key", request.Header.Get("X-Subscription-Token"))
+	assert.Equal(t, &WebResponse{
+		Query: "golang",
+		Results: []Result{
+			{Title: "The Go Programming Language", URL: "https://go.dev/", Description: "Go is ...", Age: "2 days ago"},
+		},
+	}, resp)
+}
+
+func TestClient_WebNoResults(t *testing.T) {
+	// given
+	client := setupClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"query": {"original": "zzz"}}`))
+	})
+
+	// when
+	resp, err := client.Web(context.Background(), Query{Query: "zzz"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []Result{}, resp.Results)
+}
+
+func TestClient_News(t *testing.T) {
+	// given
+	client := setupClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/news/search", r.URL.Path)
+		w.Write([]byte(`{
+			"query": {"original": "go release"},
+			"results": [
+				{"title": "Go 1.22 is released", "url": "https://go.dev/blog/go1.22", "description": "...", "meta_url": {"hostname": "go.dev"}}
+			]
+		}`))
+	})
+
+	// when
+	resp, err := client.News(context.Background(), Query{Query: "go release"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []NewsResult{
+		{Result: Result{Title: "Go 1.22 is released", URL: "https://go.dev/blog/go1.22", Description: "..."}, Source: "go.dev"},
+	}, resp.Results)
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	// given
+	client := setupClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "1, 15000")
+		w.Header().Set("X-RateLimit-Remaining", "0, 14000")
+		w.Header().Set("X-RateLimit-Reset", "1, 1419704")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	// when
+	_, err := client.Web(context.Background(), Query{Query: "golang"})
+
+	// then
+	var rateLimited *RateLimitError
+	assert.ErrorAs(t, err, &rateLimited)
+	assert.Equal(t, time.Second, rateLimited.RetryAfter())
+	assert.Equal(t, "0, 14000", rateLimited.Remaining)
+	assert.ErrorIs(t, err, errs.RateLimited)
+}
+
+func TestClient_APIError(t *testing.T) {
+	// given
+	client := setupClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error": "invalid count"}`))
+	})
+
+	// when
+	_, err := client.Web(context.Background(), Query{Query: "golang", Count: 100})
+
+	// then
+	assert.Equal(t, &APIError{StatusCode: http.StatusUnprocessableEntity, Body: `{"error": "invalid count"}`}, err)
+	assert.NotErrorIs(t, err, errs.Unauthorized)
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	// given
+	client := setupClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	// when
+	_, err := client.Web(context.Background(), Query{Query: "golang"})
+
+	// then
+	assert.ErrorIs(t, err, errs.Unauthorized)
+}
+
+func TestClient_Validation(t *testing.T) {
+	_, err := New("")
+	assert.Error(t, err)
+
+	client, err := New("key")
+	assert.NoError(t, err)
+	_, err = client.Web(context.Background(), Query{Query: " "})
+	assert.Error(t, err)
+}
+
+func TestRateLimitReset(t *testing.T) {
+	assert.Equal(t, time.Second, rateLimitReset("0, 100", "1, 500"))
+	assert.Equal(t, 500*time.Second, rateLimitReset("0, 0", "1, 500"))
+	assert.Equal(t, time.Second, rateLimitReset("", "1, 500"))
+	assert.Zero(t, rateLimitReset("0", ""))
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package secmem
+
+import "errors"
+
+func lock(data []byte) error {
+	return errors.New("memory locking is not supported on this platform")
+}
+
+func unlock(data []byte) {}
@@ -0,0 +1,18 @@
+//go:build linux || darwin
+
+package secmem
+
+import "syscall"
+
+func lock(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return syscall.Mlock(data)
+}
+
+func unlock(data []byte) {
+	if len(data) > 0 {
+		syscall.Munlock(data)
+	}
+}
@@ -0,0 +1,115 @@
+// Package secmem keeps sensitive values out of ordinary strings: a Buffer holds plaintext in memory
+// that is locked against swapping where supported, never prints its contents and is zeroed on Destroy.
+package secmem
+
+import (
+	"fmt"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+type Buffer struct {
+	mu     sync.Mutex
+	data   []byte
+	locked bool
+}
+
+// New copies data into a Buffer and zeroes the source slice.
+func New(data []byte) *Buffer {
+	b := &Buffer{
+		data: make([]byte, len(data)),
+	}
+	copy(b.data, data)
+	wipe(data)
+	b.locked = lock(b.data) == nil
+	return b
+}
+
+// NewString copies s into a Buffer. The original string can't be zeroed, so prefer New
+// when the secret is available as a byte slice.
+func NewString(s string) *Buffer {
+	return New([]byte(s))
+}
+
+// Use calls fn with the plaintext. The slice must not be retained after fn returns.
+// Returns an error if the buffer was destroyed.
+func (b *Buffer) Use(fn func(plaintext []byte) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.data == nil {
+		return fmt.Errorf("secure buffer is destroyed")
+	}
+	return fn(b.data)
+}
+
+// Reveal returns the plaintext as a string, copying it into ordinary memory.
+// Use it only at the boundary with APIs that require a string.
+func (b *Buffer) Reveal() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.data)
+}
+
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.data)
+}
+
+// Locked reports whether the memory is locked against swapping.
+func (b *Buffer) Locked() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.locked
+}
+
+// Destroy zeroes and unlocks the memory. The buffer is unusable afterwards.
+func (b *Buffer) Destroy() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.data == nil {
+		return
+	}
+	wipe(b.data)
+	if b.locked {
+		unlock(b.data)
+		b.locked = false
+	}
+	b.data = nil
+}
+
+func (b *Buffer) Destroyed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data == nil
+}
+
+func (b *Buffer) String() string {
+	return redacted
+}
+
+func (b *Buffer) GoString() string {
+	return redacted
+}
+
+// Format keeps the plaintext out of every fmt verb, including %x and %v of containing structs.
+func (b *Buffer) Format(f fmt.State, verb rune) {
+	f.Write([]byte(redacted))
+}
+
+func (b *Buffer) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+func (b *Buffer) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+func wipe(data []byte) {
+	for i := range data {
+		data[i] = 0
+	}
+}
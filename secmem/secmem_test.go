@@ -0,0 +1,75 @@
+package secmem
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuffer(t *testing.T) {
+	// given
+	source := []byte("bot-token")
+
+	// when
+	b := New(source)
+
+	// then
+	assert.Equal(t, make([]byte, len(source)), source, "source must be zeroed")
+	assert.Equal(t, "bot-token", b.Reveal())
+	assert.Equal(t, 9, b.Len())
+
+	err := b.Use(func(plaintext []byte) error {
+		assert.Equal(t, []byte("bot-token"), plaintext)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestBuffer_Redacted(t *testing.T) {
+	b := NewString("bot-token")
+	config := struct {
+		Token *Buffer `json:"token"`
+	}{b}
+
+	for _, s := range []string{
+		b.String(),
+		fmt.Sprintf("%v %s %x %q %#v", b, b, b, b, b),
+		fmt.Sprintf("%+v", config),
+	} {
+		assert.NotContains(t, s, "bot-token")
+	}
+
+	var logged strings.Builder
+	logger := log.New(&logged, "", 0)
+	logger.Println("token:", b)
+	assert.Equal(t, "token: [REDACTED]\n", logged.String())
+
+	data, err := json.Marshal(config)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"token": "[REDACTED]"}`, string(data))
+}
+
+func TestBuffer_Destroy(t *testing.T) {
+	// given
+	b := NewString("bot-token")
+	var leaked []byte
+	b.Use(func(plaintext []byte) error {
+		leaked = plaintext
+		return nil
+	})
+
+	// when
+	b.Destroy()
+	b.Destroy()
+
+	// then
+	assert.True(t, b.Destroyed())
+	assert.False(t, b.Locked())
+	assert.Equal(t, make([]byte, 9), leaked, "memory must be zeroed")
+	assert.Equal(t, "", b.Reveal())
+	assert.Error(t, b.Use(func(plaintext []byte) error { return nil }))
+}
@@ -0,0 +1,81 @@
+// Package signing provides HMAC-SHA256 helpers for webhook verification and signed payloads.
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrTokenExpired     = errors.New("token expired")
+)
+
+const timestampSize = 8
+
+// Sign returns the hex encoded HMAC-SHA256 of payload.
+func Sign(key []byte, payload []byte) string {
+	return hex.EncodeToString(mac(key, payload))
+}
+
+// Verify checks a hex encoded HMAC-SHA256 signature of payload in constant time.
+func Verify(key []byte, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(mac(key, payload), expected)
+}
+
+// SignToken returns a URL-safe token carrying payload, the signing time and a signature.
+// The token only uses [A-Za-z0-9_-], so it fits Telegram deep-link start parameters
+// as long as the payload is short enough (the token is 54 characters plus 4/3 of the payload).
+func SignToken(key []byte, payload []byte) string {
+	return signTokenAt(key, payload, time.Now())
+}
+
+// VerifyToken checks a token created by SignToken and returns its payload.
+// A positive maxAge rejects tokens signed earlier than maxAge ago with ErrTokenExpired.
+func VerifyToken(key []byte, token string, maxAge time.Duration) ([]byte, error) {
+	return verifyTokenAt(key, token, maxAge, time.Now())
+}
+
+func signTokenAt(key []byte, payload []byte, now time.Time) string {
+	data := make([]byte, 0, len(payload)+timestampSize+sha256.Size)
+	data = append(data, payload...)
+	data = binary.BigEndian.AppendUint64(data, uint64(now.Unix()))
+	data = append(data, mac(key, data)...)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func verifyTokenAt(key []byte, token string, maxAge time.Duration, now time.Time) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) < timestampSize+sha256.Size {
+		return nil, ErrMalformedToken
+	}
+
+	signed, signature := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if !hmac.Equal(mac(key, signed), signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	payload, timestamp := signed[:len(signed)-timestampSize], signed[len(signed)-timestampSize:]
+	signedAt := time.Unix(int64(binary.BigEndian.Uint64(timestamp)), 0)
+	if maxAge > 0 && now.Sub(signedAt) > maxAge {
+		return nil, ErrTokenExpired
+	}
+
+	return payload, nil
+}
+
+func mac(key []byte, payload []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(payload)
+	return h.Sum(nil)
+}
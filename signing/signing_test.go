@@ -0,0 +1,52 @@
+package signing
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	key := []byte("key")
+	payload := []byte(`{"event":"push"}`)
+
+	signature := Sign(key, payload)
+
+	assert.Len(t, signature, 64)
+	assert.True(t, Verify(key, payload, signature))
+	assert.False(t, Verify([]byte("other"), payload, signature))
+	assert.False(t, Verify(key, []byte(`{"event":"pull"}`), signature))
+	assert.False(t, Verify(key, payload, "not hex"))
+}
+
+func TestSignTokenVerifyToken(t *testing.T) {
+	// given
+	key := []byte("key")
+	now := time.Now()
+
+	// when
+	token := signTokenAt(key, []byte("chat42"), now)
+
+	// then
+	assert.Regexp(t, regexp.MustCompile(`^[A-Za-z0-9_-]+$`), token)
+	assert.LessOrEqual(t, len(token), 64)
+
+	payload, err := verifyTokenAt(key, token, time.Hour, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("chat42"), payload)
+
+	_, err = verifyTokenAt(key, token, time.Hour, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrTokenExpired)
+
+	payload, err = verifyTokenAt(key, token, 0, now.Add(2*time.Hour))
+	assert.NoError(t, err, "zero maxAge never expires")
+	assert.Equal(t, []byte("chat42"), payload)
+
+	_, err = VerifyToken([]byte("other"), token, 0)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = VerifyToken(key, "short", 0)
+	assert.ErrorIs(t, err, ErrMalformedToken)
+}
@@ -0,0 +1,18 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+)
+
+// BackupTo writes a consistent snapshot of a sqlite database to path using VACUUM INTO.
+// The database stays available for reads and writes while the snapshot is taken.
+// path must not point to an existing non-empty file.
+func (db *SqlDb) BackupTo(ctx context.Context, path string) error {
+	if db.dialect != DialectSqlite {
+		return fmt.Errorf("backup is not supported for dialect: %s", db.dialect)
+	}
+
+	_, err := db.ExecContext(ctx, "VACUUM INTO $1", path)
+	return err
+}
@@ -0,0 +1,45 @@
+package sqldb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupTo(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	db, err := InitSqlite(filepath.Join(dir, "source.db"))
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE test_backup (a TEXT NOT NULL); INSERT INTO test_backup (a) VALUES ('foo');")
+	if err != nil {
+		t.Fatalf("Failed to prepare source database: %v", err)
+	}
+	backupPath := filepath.Join(dir, "backup.db")
+
+	// when
+	err = db.BackupTo(context.Background(), backupPath)
+
+	// then
+	assert.NoError(t, err)
+
+	backup, err := InitSqlite(backupPath)
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer backup.Close()
+
+	var a string
+	err = backup.QueryRow("SELECT a FROM test_backup").Scan(&a)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", a)
+
+	// a second backup to the same path must not overwrite it
+	assert.Error(t, db.BackupTo(context.Background(), backupPath))
+}
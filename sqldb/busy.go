@@ -0,0 +1,65 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	DefaultBusyTimeout    = 5 * time.Second
+	DefaultBusyRetries    = 5
+	DefaultBusyRetryDelay = 50 * time.Millisecond
+)
+
+type busyRetry struct {
+	attempts int
+	delay    time.Duration
+}
+
+// SetBusyRetry configures how many times ExecCtx retries a write that failed with SQLITE_BUSY/SQLITE_LOCKED.
+// The delay grows linearly with every attempt. Zero attempts disables retrying.
+func (db *SqlDb) SetBusyRetry(attempts int, delay time.Duration) {
+	db.busyRetry = busyRetry{
+		attempts: attempts,
+		delay:    delay,
+	}
+}
+
+func (db *SqlDb) retryOnBusy(ctx context.Context, fn func() error) error {
+	err := fn()
+	for attempt := 1; attempt <= db.busyRetry.attempts && isBusy(err); attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(db.busyRetry.delay * time.Duration(attempt)):
+		}
+		err = fn()
+	}
+	return err
+}
+
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
+
+// withSqliteParam adds a connection parameter to a sqlite DSN unless it's already present.
+func withSqliteParam(dsn string, key string, value string) string {
+	pos := strings.IndexRune(dsn, '?')
+	if pos >= 0 {
+		params, err := url.ParseQuery(dsn[pos+1:])
+		if err == nil && params.Has(key) {
+			return dsn
+		}
+		return dsn + "&" + key + "=" + url.QueryEscape(value)
+	}
+	return dsn + "?" + key + "=" + url.QueryEscape(value)
+}
@@ -0,0 +1,87 @@
+package sqldb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSqliteParam(t *testing.T) {
+	assert.Equal(t, ":memory:?_busy_timeout=5000", withSqliteParam(":memory:", "_busy_timeout", "5000"))
+	assert.Equal(t, "file:test.db?cache=shared&_busy_timeout=5000", withSqliteParam("file:test.db?cache=shared", "_busy_timeout", "5000"))
+	assert.Equal(t, "test.db?_busy_timeout=100", withSqliteParam("test.db?_busy_timeout=100", "_busy_timeout", "5000"))
+}
+
+func TestExecCtx_RetriesOnBusy(t *testing.T) {
+	// given
+	db, locker := setupLockedDb(t)
+	defer db.Close()
+	defer locker.Close()
+	db.SetBusyRetry(10, 20*time.Millisecond)
+
+	tx, err := locker.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	_, err = tx.Exec("INSERT INTO test_busy (a) VALUES ('locker')")
+	if err != nil {
+		t.Fatalf("Failed to lock database: %v", err)
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		tx.Commit()
+	}()
+
+	// when
+	_, err = db.ExecCtx(context.Background(), "INSERT INTO test_busy (a) VALUES ($1)", "foo")
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestExecCtx_BusyWithoutRetry(t *testing.T) {
+	// given
+	db, locker := setupLockedDb(t)
+	defer db.Close()
+	defer locker.Close()
+	db.SetBusyRetry(0, 0)
+
+	tx, err := locker.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("INSERT INTO test_busy (a) VALUES ('locker')")
+	if err != nil {
+		t.Fatalf("Failed to lock database: %v", err)
+	}
+
+	// when
+	_, err = db.ExecCtx(context.Background(), "INSERT INTO test_busy (a) VALUES ($1)", "foo")
+
+	// then
+	assert.True(t, isBusy(err), "expected SQLITE_BUSY, got: %v", err)
+}
+
+func setupLockedDb(t *testing.T) (*SqlDb, *SqlDb) {
+	path := filepath.Join(t.TempDir(), "busy.db")
+
+	db, err := InitSqlite(path + "?_busy_timeout=0")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	locker, err := InitSqlite(path + "?_busy_timeout=0")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+
+	_, err = db.Exec("CREATE TABLE test_busy (a TEXT NOT NULL)")
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	return db, locker
+}
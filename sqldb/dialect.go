@@ -0,0 +1,101 @@
+package sqldb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Dialect int
+
+const (
+	DialectSqlite Dialect = iota
+	DialectMySQL
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectSqlite:
+		return "sqlite"
+	case DialectMySQL:
+		return "mysql"
+	default:
+		return fmt.Sprintf("Dialect(%d)", int(d))
+	}
+}
+
+// rebind rewrites $N placeholders into the form expected by the dialect and returns the
+// arguments to pass along. Queries are written with $N placeholders and passed through rebind
+// before execution. Placeholders may repeat and appear in any order; ones inside string
+// literals, quoted identifiers and comments are left untouched.
+//
+// SQLite gets ?N, which binds by number. MySQL only has positional ?, so args are rebuilt in
+// placeholder order. Placeholders without a matching argument are dropped from args, so the
+// driver reports the mismatch.
+func (d Dialect) rebind(query string, args []any) (string, []any) {
+	var sb strings.Builder
+	var bound []any
+	found := false
+	for i := 0; i < len(query); i++ {
+		if end, ok := d.skipNonCode(query, i); ok {
+			sb.WriteString(query[i : end+1])
+			i = end
+			continue
+		}
+
+		c := query[i]
+		if c != '$' || i+1 >= len(query) || !isDigit(query[i+1]) || i > 0 && isWordChar(query[i-1]) {
+			sb.WriteByte(c)
+			continue
+		}
+		end := i + 1
+		for end < len(query) && isDigit(query[end]) {
+			end++
+		}
+		found = true
+		if d == DialectMySQL {
+			sb.WriteByte('?')
+			n, err := strconv.Atoi(query[i+1 : end])
+			if err == nil && n >= 1 && n <= len(args) {
+				bound = append(bound, args[n-1])
+			}
+		} else {
+			sb.WriteString("?" + query[i+1:end])
+		}
+		i = end - 1
+	}
+
+	if d != DialectMySQL || !found {
+		return sb.String(), args
+	}
+	return sb.String(), bound
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (d Dialect) migrationsInitialScript() string {
+	switch d {
+	case DialectMySQL:
+		return migrationsInitialScriptMySQL
+	default:
+		return migrationsInitialScript
+	}
+}
+
+// tableExistsQuery returns a query counting tables named $1 in the current database.
+func (d Dialect) tableExistsQuery() string {
+	switch d {
+	case DialectMySQL:
+		return "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = $1"
+	default:
+		return "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = $1"
+	}
+}
+
+// transactionalDDL reports whether schema changes can be rolled back as part of a transaction.
+// MySQL implicitly commits on most DDL statements.
+func (d Dialect) transactionalDDL() bool {
+	return d != DialectMySQL
+}
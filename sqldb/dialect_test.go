@@ -0,0 +1,77 @@
+package sqldb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebind(t *testing.T) {
+	query := "INSERT INTO migrations (file, md5, applied_at) VALUES ($1, $2, $3)"
+	args := []any{"a", "b", "c"}
+
+	sqliteQuery, sqliteArgs := DialectSqlite.rebind(query, args)
+	mysqlQuery, mysqlArgs := DialectMySQL.rebind(query, args)
+
+	assert.Equal(t, "INSERT INTO migrations (file, md5, applied_at) VALUES (?1, ?2, ?3)", sqliteQuery)
+	assert.Equal(t, args, sqliteArgs)
+	assert.Equal(t, "INSERT INTO migrations (file, md5, applied_at) VALUES (?, ?, ?)", mysqlQuery)
+	assert.Equal(t, args, mysqlArgs)
+}
+
+func TestRebind_ReusedAndReorderedPlaceholders(t *testing.T) {
+	query := "SELECT * FROM t WHERE a = $2 OR b = $1 OR c = $2"
+
+	sqliteQuery, sqliteArgs := DialectSqlite.rebind(query, []any{1, 2})
+	mysqlQuery, mysqlArgs := DialectMySQL.rebind(query, []any{1, 2})
+
+	assert.Equal(t, "SELECT * FROM t WHERE a = ?2 OR b = ?1 OR c = ?2", sqliteQuery)
+	assert.Equal(t, []any{1, 2}, sqliteArgs)
+	assert.Equal(t, "SELECT * FROM t WHERE a = ? OR b = ? OR c = ?", mysqlQuery)
+	assert.Equal(t, []any{2, 1, 2}, mysqlArgs)
+}
+
+func TestRebind_SkipsLiteralsAndComments(t *testing.T) {
+	query := "SELECT '$1', \"$2\", `$3`, price$1 -- costs $1\nFROM t /* $2 */ WHERE a = $1"
+
+	mysqlQuery, mysqlArgs := DialectMySQL.rebind(query, []any{"x"})
+
+	assert.Equal(t, "SELECT '$1', \"$2\", `$3`, price$1 -- costs $1\nFROM t /* $2 */ WHERE a = ?", mysqlQuery)
+	assert.Equal(t, []any{"x"}, mysqlArgs)
+}
+
+func TestRebind_MissingArgument(t *testing.T) {
+	query, args := DialectMySQL.rebind("SELECT $1, $3", []any{1, 2})
+
+	assert.Equal(t, "SELECT ?, ?", query)
+	assert.Equal(t, []any{1}, args)
+}
+
+func TestExecCtx_ReusedAndReorderedPlaceholders(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	_, err = db.ExecCtx(ctx, "CREATE TABLE t (a TEXT, b TEXT)")
+	assert.NoError(t, err)
+
+	// when
+	_, err = db.ExecCtx(ctx, "INSERT INTO t (b, a) VALUES ($2, $1)", "first", "second")
+	assert.NoError(t, err)
+	var a, b string
+	err = db.QueryRowCtx(ctx, "SELECT a, b FROM t WHERE a = $1 OR b = $1", "first").Scan(&a, &b)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, []string{a, b})
+}
+
+func TestInitMySQL_InvalidDSN(t *testing.T) {
+	_, err := InitMySQL("not a dsn")
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// GoMigration is a data migration written in Go. It runs inside a transaction
+// which also records the migration as applied.
+type GoMigration func(ctx context.Context, tx *sql.Tx) error
+
+// RegisterGoMigration adds a Go migration to the migration history. It's ordered by version prefix together
+// with SQL files, e.g. "3_backfill_users" runs between "2_users.sql" and "4_index.sql".
+// The name is recorded in the migrations table instead of a file name, and its checksum is derived
+// from the name, so renaming a Go migration makes it run again.
+// Go migrations run with the application's migrations, not with RunPackageMigrationsFS.
+func (db *SqlDb) RegisterGoMigration(name string, fn GoMigration) error {
+	db.goMigrationsMu.Lock()
+	defer db.goMigrationsMu.Unlock()
+
+	if db.goMigrations == nil {
+		db.goMigrations = map[string]GoMigration{}
+	}
+	if _, ok := db.goMigrations[name]; ok {
+		return fmt.Errorf("go migration already registered: %s", name)
+	}
+	db.goMigrations[name] = fn
+	return nil
+}
+
+func goMigrationMd5(name string) string {
+	return migrationMd5([]byte("go:" + name))
+}
+
+func (db *SqlDb) applyAndSaveGoMigration(ctx context.Context, name string, md5 string, fn GoMigration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = fn(ctx, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = db.saveMigrationInfo(ctx, tx, name, md5)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,36 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+)
+
+// HealthCheck pings the database and verifies the migrations table is readable.
+// If migrations were run through this SqlDb, it also verifies the latest migration is still recorded
+// as applied, so a database restored from an older snapshot is reported as unhealthy.
+func (db *SqlDb) HealthCheck(ctx context.Context) error {
+	err := db.PingContext(ctx)
+	if err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+
+	var count int
+	err = db.QueryRowCtx(ctx, "SELECT COUNT(*) FROM migrations").Scan(&count)
+	if err != nil {
+		return fmt.Errorf("migrations table is not readable: %w", err)
+	}
+
+	latest, ok := db.latestMigration.Load().(string)
+	if !ok {
+		return nil
+	}
+	applied, err := db.checkIfMigrationPreviouslyApplied(ctx, latest)
+	if err != nil {
+		return fmt.Errorf("failed to check latest migration: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("latest migration is not applied: %s", latest)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package sqldb
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheck(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	path := setupMigrationFiles([]string{"CREATE TABLE test_health (a TEXT);"})
+	defer removeTempDir(path)
+
+	// then
+	assert.Error(t, db.HealthCheck(ctx), "migrations table doesn't exist yet")
+
+	assert.NoError(t, db.RunMigrations(path))
+	assert.NoError(t, db.HealthCheck(ctx))
+
+	_, err = db.Exec("DELETE FROM migrations")
+	if err != nil {
+		t.Fatalf("Failed to delete migrations: %v", err)
+	}
+	assert.Error(t, db.HealthCheck(ctx), "latest migration is missing")
+}
+
+func TestHealthCheck_PackageMigrationsKeepLatest(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	app := fstest.MapFS{"1_app.sql": {Data: []byte("CREATE TABLE test_health_app (a TEXT);")}}
+	pkg := fstest.MapFS{"1_pkg.sql": {Data: []byte("CREATE TABLE test_health_pkg (a TEXT);")}}
+	assert.NoError(t, db.RunMigrationsFS(app, "."))
+
+	// when
+	assert.NoError(t, db.RunPackageMigrationsFS(pkg, "."))
+
+	// then
+	_, err = db.Exec("DELETE FROM migrations WHERE file = '1_app.sql'")
+	if err != nil {
+		t.Fatalf("Failed to delete migration: %v", err)
+	}
+	assert.Error(t, db.HealthCheck(ctx), "the application's latest migration is missing")
+}
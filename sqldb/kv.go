@@ -0,0 +1,132 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+)
+
+const kvInitialScript = `
+CREATE TABLE IF NOT EXISTS kv (
+    k TEXT NOT NULL,
+    v TEXT NOT NULL,
+    expires_at BIGINT NULL,
+    PRIMARY KEY (k)
+);
+`
+
+const kvInitialScriptMySQL = `
+CREATE TABLE IF NOT EXISTS kv (
+    k VARCHAR(255) NOT NULL,
+    v LONGTEXT NOT NULL,
+    expires_at BIGINT NULL,
+    PRIMARY KEY (k)
+);
+`
+
+var ErrKeyNotFound = errs.New(errs.NotFound, "key not found")
+
+// KV is a durable key-value store with JSON values and optional TTL, kept in the kv table.
+type KV struct {
+	db  *SqlDb
+	now func() time.Time
+}
+
+// NewKV creates the kv table if needed and returns a store on top of it.
+func NewKV(ctx context.Context, db *SqlDb) (*KV, error) {
+	script := kvInitialScript
+	if db.dialect == DialectMySQL {
+		script = kvInitialScriptMySQL
+	}
+	_, err := db.ExecCtx(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KV{
+		db:  db,
+		now: time.Now,
+	}, nil
+}
+
+// Set stores value marshaled as JSON. A zero ttl means the key never expires.
+func (kv *KV) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt sql.NullInt64
+	if ttl > 0 {
+		expiresAt = sql.NullInt64{Int64: kv.now().Add(ttl).UnixMilli(), Valid: true}
+	}
+
+	_, err = kv.db.ExecCtx(ctx, "REPLACE INTO kv (k, v, expires_at) VALUES ($1, $2, $3)", key, string(data), expiresAt)
+	return err
+}
+
+// Get unmarshals the value stored under key into value.
+// Returns ErrKeyNotFound when the key is missing or expired.
+func (kv *KV) Get(ctx context.Context, key string, value any) error {
+	var data string
+	err := kv.db.QueryRowCtx(ctx,
+		"SELECT v FROM kv WHERE k = $1 AND (expires_at IS NULL OR expires_at > $2)",
+		key, kv.now().UnixMilli(),
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return ErrKeyNotFound
+	} else if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(data), value)
+}
+
+func (kv *KV) Delete(ctx context.Context, key string) error {
+	_, err := kv.db.ExecCtx(ctx, "DELETE FROM kv WHERE k = $1", key)
+	return err
+}
+
+// List returns sorted keys starting with prefix, skipping expired ones.
+func (kv *KV) List(ctx context.Context, prefix string) ([]string, error) {
+	rows, err := kv.db.QueryCtx(ctx,
+		"SELECT k FROM kv WHERE k LIKE $1 ESCAPE '!' AND (expires_at IS NULL OR expires_at > $2) ORDER BY k",
+		escapeLike(prefix)+"%", kv.now().UnixMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		err = rows.Scan(&key)
+		if err != nil {
+			return nil, err
+		}
+		// LIKE may be case-insensitive depending on the database
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, rows.Err()
+}
+
+// DeleteExpired removes expired keys and returns how many were removed.
+func (kv *KV) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := kv.db.ExecCtx(ctx, "DELETE FROM kv WHERE expires_at IS NOT NULL AND expires_at <= $1", kv.now().UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
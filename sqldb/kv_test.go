@@ -0,0 +1,95 @@
+package sqldb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+type kvTestValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestKV_SetGetDelete(t *testing.T) {
+	// given
+	kv := setupKV(t)
+	ctx := context.Background()
+	expected := kvTestValue{Name: "foo", Count: 42}
+
+	// when
+	err := kv.Set(ctx, "settings", expected, 0)
+
+	// then
+	assert.NoError(t, err)
+
+	var actual kvTestValue
+	assert.NoError(t, kv.Get(ctx, "settings", &actual))
+	assert.Equal(t, expected, actual)
+
+	assert.NoError(t, kv.Set(ctx, "settings", kvTestValue{Name: "bar"}, 0))
+	assert.NoError(t, kv.Get(ctx, "settings", &actual))
+	assert.Equal(t, "bar", actual.Name)
+
+	assert.NoError(t, kv.Delete(ctx, "settings"))
+	assert.ErrorIs(t, kv.Get(ctx, "settings", &actual), ErrKeyNotFound)
+	assert.ErrorIs(t, kv.Get(ctx, "settings", &actual), errs.NotFound)
+}
+
+func TestKV_TTL(t *testing.T) {
+	// given
+	kv := setupKV(t)
+	ctx := context.Background()
+	now := time.Now()
+	kv.now = func() time.Time { return now }
+
+	assert.NoError(t, kv.Set(ctx, "short", "a", time.Minute))
+	assert.NoError(t, kv.Set(ctx, "forever", "b", 0))
+
+	// when
+	now = now.Add(2 * time.Minute)
+
+	// then
+	var value string
+	assert.ErrorIs(t, kv.Get(ctx, "short", &value), ErrKeyNotFound)
+	assert.NoError(t, kv.Get(ctx, "forever", &value))
+	assert.Equal(t, "b", value)
+
+	deleted, err := kv.DeleteExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestKV_List(t *testing.T) {
+	// given
+	kv := setupKV(t)
+	ctx := context.Background()
+	for _, key := range []string{"user:2", "user:1", "User:3", "user_x", "chat:1"} {
+		assert.NoError(t, kv.Set(ctx, key, true, 0))
+	}
+
+	// when
+	keys, err := kv.List(ctx, "user:")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user:1", "user:2"}, keys)
+}
+
+func setupKV(t *testing.T) *KV {
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	kv, err := NewKV(context.Background(), db)
+	if err != nil {
+		t.Fatalf("NewKV failed: %v", err)
+	}
+	return kv
+}
@@ -0,0 +1,94 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+type MaintenanceOptions struct {
+	Interval time.Duration
+	// Vacuum rebuilds the database file, returning free pages to the filesystem.
+	Vacuum bool
+	// Analyze refreshes query planner statistics.
+	Analyze bool
+	// WALCheckpoint moves WAL contents into the database file and truncates the WAL.
+	WALCheckpoint bool
+}
+
+// Maintenance periodically runs sqlite housekeeping, see StartMaintenance.
+type Maintenance struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// RunMaintenance runs a single pass of the enabled maintenance tasks on a sqlite database.
+func (db *SqlDb) RunMaintenance(ctx context.Context, opts MaintenanceOptions) error {
+	if db.dialect != DialectSqlite {
+		return fmt.Errorf("maintenance is not supported for dialect: %s", db.dialect)
+	}
+
+	if opts.WALCheckpoint {
+		_, err := db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+		if err != nil {
+			return fmt.Errorf("wal checkpoint failed: %w", err)
+		}
+	}
+	if opts.Analyze {
+		_, err := db.ExecContext(ctx, "ANALYZE")
+		if err != nil {
+			return fmt.Errorf("analyze failed: %w", err)
+		}
+	}
+	if opts.Vacuum {
+		_, err := db.ExecContext(ctx, "VACUUM")
+		if err != nil {
+			return fmt.Errorf("vacuum failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// StartMaintenance runs maintenance every opts.Interval in background until Stop is called
+// or ctx is done. Failures are logged and don't stop subsequent runs.
+func (db *SqlDb) StartMaintenance(ctx context.Context, opts MaintenanceOptions) (*Maintenance, error) {
+	if db.dialect != DialectSqlite {
+		return nil, fmt.Errorf("maintenance is not supported for dialect: %s", db.dialect)
+	}
+	if opts.Interval <= 0 {
+		return nil, fmt.Errorf("maintenance interval must be positive: %s", opts.Interval)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := &Maintenance{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := db.RunMaintenance(ctx, opts)
+				if err != nil && ctx.Err() == nil {
+					log.Println("Database maintenance failed: ", err)
+				}
+			}
+		}
+	}()
+
+	return m, nil
+}
+
+// Stop stops the maintenance loop and waits for a running pass to finish.
+func (m *Maintenance) Stop() {
+	m.cancel()
+	<-m.done
+}
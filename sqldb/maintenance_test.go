@@ -0,0 +1,70 @@
+package sqldb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunMaintenance(t *testing.T) {
+	// given
+	db, err := InitSqliteWithOptions(filepath.Join(t.TempDir(), "maintenance.db"), SqliteOptions{JournalMode: JournalModeWAL})
+	if err != nil {
+		t.Fatalf("InitSqliteWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE test_maintenance (a TEXT); INSERT INTO test_maintenance (a) VALUES ('foo');")
+	if err != nil {
+		t.Fatalf("Failed to prepare database: %v", err)
+	}
+
+	// when
+	err = db.RunMaintenance(context.Background(), MaintenanceOptions{Vacuum: true, Analyze: true, WALCheckpoint: true})
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestStartMaintenance(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE test_maintenance (a TEXT); CREATE INDEX test_maintenance_a ON test_maintenance (a); INSERT INTO test_maintenance (a) VALUES ('foo');")
+	if err != nil {
+		t.Fatalf("Failed to prepare database: %v", err)
+	}
+
+	// when
+	m, err := db.StartMaintenance(context.Background(), MaintenanceOptions{Interval: 10 * time.Millisecond, Analyze: true})
+	if err != nil {
+		t.Fatalf("StartMaintenance failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	m.Stop()
+
+	// then
+	var stats int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'sqlite_stat1'").Scan(&stats)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats, "ANALYZE should have created sqlite_stat1")
+}
+
+func TestStartMaintenance_InvalidInterval(t *testing.T) {
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.StartMaintenance(context.Background(), MaintenanceOptions{Analyze: true})
+	assert.Error(t, err)
+}
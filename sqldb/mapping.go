@@ -0,0 +1,73 @@
+package sqldb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// structField describes a struct field mapped to a column with a `db:"column[,pk][,auto]"` tag.
+type structField struct {
+	column string
+	index  []int
+	pk     bool
+	auto   bool
+}
+
+// structFields returns the columns of a struct type. Only fields tagged with db are mapped.
+func structFields(t reflect.Type) ([]structField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got: %s", t)
+	}
+
+	fields := []structField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("db")
+		if !ok || tag == "-" || !f.IsExported() {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		field := structField{
+			column: parts[0],
+			index:  f.Index,
+		}
+		if field.column == "" {
+			return nil, fmt.Errorf("empty column name for field: %s", f.Name)
+		}
+		for _, opt := range parts[1:] {
+			switch opt {
+			case "pk":
+				field.pk = true
+			case "auto":
+				field.auto = true
+			default:
+				return nil, fmt.Errorf("unknown db tag option %q for field: %s", opt, f.Name)
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no db tagged fields in: %s", t)
+	}
+	return fields, nil
+}
+
+func columnNames(fields []structField) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.column
+	}
+	return names
+}
+
+// placeholders returns n comma separated $N placeholders starting at $start.
+func placeholders(start int, n int) string {
+	ps := make([]string, n)
+	for i := range ps {
+		ps[i] = fmt.Sprintf("$%d", start+i)
+	}
+	return strings.Join(ps, ", ")
+}
@@ -0,0 +1,29 @@
+package sqldb
+
+import (
+	"strings"
+	"time"
+)
+
+// Metrics receives per-query instrumentation from the Ctx helpers.
+// Implementations usually forward it to Prometheus counters and histograms; pool state is
+// available through Stats and can be exported alongside.
+type Metrics interface {
+	// QueryDone is called after every statement. operation is the lowercased leading SQL keyword
+	// (select, insert, update, delete, ...) to keep label cardinality low.
+	QueryDone(operation string, duration time.Duration, err error)
+}
+
+// SetMetrics installs query instrumentation. Pass nil to disable.
+// Should be called before the database is used concurrently.
+func (db *SqlDb) SetMetrics(metrics Metrics) {
+	db.metrics = metrics
+}
+
+func queryOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	return strings.ToLower(strings.TrimLeft(fields[0], "("))
+}
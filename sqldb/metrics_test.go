@@ -0,0 +1,51 @@
+package sqldb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingMetrics struct {
+	operations []string
+	errors     int
+}
+
+func (m *recordingMetrics) QueryDone(operation string, duration time.Duration, err error) {
+	m.operations = append(m.operations, operation)
+	if err != nil {
+		m.errors++
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	metrics := &recordingMetrics{}
+	db.SetMetrics(metrics)
+
+	// when
+	db.ExecCtx(ctx, "CREATE TABLE test_metrics (a TEXT)")
+	db.ExecCtx(ctx, "\n  INSERT INTO test_metrics (a) VALUES ($1)", "foo")
+	rows, _ := db.QueryCtx(ctx, "SELECT a FROM test_metrics")
+	rows.Close()
+	db.QueryRowCtx(ctx, "SELECT missing FROM test_metrics")
+
+	// then
+	assert.Equal(t, []string{"create", "insert", "select", "select"}, metrics.operations)
+	assert.Equal(t, 1, metrics.errors)
+}
+
+func TestQueryOperation(t *testing.T) {
+	assert.Equal(t, "with", queryOperation("WITH x AS (SELECT 1) SELECT * FROM x"))
+	assert.Equal(t, "select", queryOperation("(SELECT 1)"))
+	assert.Equal(t, "unknown", queryOperation("   "))
+}
@@ -0,0 +1,129 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const migrationsLockScript = `
+CREATE TABLE IF NOT EXISTS migrations_lock (
+    id INT NOT NULL,
+    locked_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+`
+
+const mysqlMigrationsLockName = "toolbox_migrations"
+
+var (
+	// MigrationsLockStaleAfter is the age after which a sqlite lock row left by a crashed instance is taken over.
+	MigrationsLockStaleAfter = 10 * time.Minute
+	// MigrationsLockRefreshInterval is how often the holder of a sqlite lock row renews it, so that
+	// long migrations aren't mistaken for crashed ones. Must be well below MigrationsLockStaleAfter.
+	MigrationsLockRefreshInterval = time.Minute
+	// MigrationsLockPollInterval is how often a waiting instance retries acquiring the lock.
+	MigrationsLockPollInterval = 100 * time.Millisecond
+)
+
+// lockMigrations blocks until this instance holds the migrations lock or ctx is done.
+// Sqlite uses a lock row in migrations_lock, MySQL uses GET_LOCK on a dedicated connection.
+func (db *SqlDb) lockMigrations(ctx context.Context) (unlock func() error, err error) {
+	if db.dialect == DialectMySQL {
+		return db.lockMigrationsMySQL(ctx)
+	}
+	return db.lockMigrationsSqlite(ctx)
+}
+
+func (db *SqlDb) lockMigrationsSqlite(ctx context.Context) (func() error, error) {
+	_, err := db.ExecContext(ctx, migrationsLockScript)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		now := time.Now()
+		_, err = db.ExecCtx(ctx, "INSERT INTO migrations_lock (id, locked_at) VALUES (1, $1)", now.UnixMilli())
+		if err == nil {
+			stopRefresh := db.refreshMigrationsLock()
+			return func() error {
+				stopRefresh()
+				_, err := db.ExecCtx(context.Background(), "DELETE FROM migrations_lock WHERE id = 1")
+				return err
+			}, nil
+		}
+		var sqliteErr sqlite3.Error
+		if !errors.As(err, &sqliteErr) || sqliteErr.Code != sqlite3.ErrConstraint {
+			return nil, err
+		}
+
+		_, err = db.ExecCtx(ctx, "DELETE FROM migrations_lock WHERE id = 1 AND locked_at < $1", now.Add(-MigrationsLockStaleAfter).UnixMilli())
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(MigrationsLockPollInterval):
+		}
+	}
+}
+
+// refreshMigrationsLock keeps locked_at of the held lock row fresh until stop is called.
+func (db *SqlDb) refreshMigrationsLock() (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(MigrationsLockRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_, err := db.ExecCtx(context.Background(), "UPDATE migrations_lock SET locked_at = $1 WHERE id = 1", time.Now().UnixMilli())
+				if err != nil {
+					log.Println("Failed to refresh migrations lock: ", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (db *SqlDb) lockMigrationsMySQL(ctx context.Context) (func() error, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var acquired int
+		err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 1)", mysqlMigrationsLockName).Scan(&acquired)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if acquired == 1 {
+			return func() error {
+				_, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", mysqlMigrationsLockName)
+				return errors.Join(err, conn.Close())
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return nil, ctx.Err()
+		default:
+		}
+	}
+}
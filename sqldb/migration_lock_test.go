@@ -0,0 +1,149 @@
+package sqldb
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockMigrations_Sqlite(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "lock.db")
+	first, err := InitSqlite(path)
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer first.Close()
+	second, err := InitSqlite(path)
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer second.Close()
+
+	unlock, err := first.lockMigrations(context.Background())
+	if err != nil {
+		t.Fatalf("lockMigrations failed: %v", err)
+	}
+
+	// when
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	_, err = second.lockMigrations(ctx)
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.NoError(t, unlock())
+	unlockSecond, err := second.lockMigrations(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, unlockSecond())
+}
+
+func TestLockMigrations_StaleLockIsTakenOver(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(migrationsLockScript)
+	if err != nil {
+		t.Fatalf("Failed to create lock table: %v", err)
+	}
+	stale := time.Now().Add(-MigrationsLockStaleAfter - time.Minute).UnixMilli()
+	_, err = db.Exec("INSERT INTO migrations_lock (id, locked_at) VALUES (1, $1)", stale)
+	if err != nil {
+		t.Fatalf("Failed to insert stale lock: %v", err)
+	}
+
+	// when
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	unlock, err := db.lockMigrations(ctx)
+
+	// then
+	assert.NoError(t, err)
+	assert.NoError(t, unlock())
+}
+
+func TestLockMigrations_HeldLockIsRefreshed(t *testing.T) {
+	// given
+	staleAfter, refreshInterval := MigrationsLockStaleAfter, MigrationsLockRefreshInterval
+	MigrationsLockStaleAfter, MigrationsLockRefreshInterval = 200*time.Millisecond, 50*time.Millisecond
+	defer func() {
+		MigrationsLockStaleAfter, MigrationsLockRefreshInterval = staleAfter, refreshInterval
+	}()
+
+	path := filepath.Join(t.TempDir(), "lock.db")
+	first, err := InitSqlite(path)
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer first.Close()
+	second, err := InitSqlite(path)
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer second.Close()
+
+	unlock, err := first.lockMigrations(context.Background())
+	if err != nil {
+		t.Fatalf("lockMigrations failed: %v", err)
+	}
+
+	// when
+	ctx, cancel := context.WithTimeout(context.Background(), 4*MigrationsLockStaleAfter)
+	defer cancel()
+	_, err = second.lockMigrations(ctx)
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NoError(t, unlock())
+}
+
+func TestRunMigrations_ConcurrentInstances(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "concurrent.db")
+	migrationsPath := setupMigrationFiles([]string{
+		"CREATE TABLE test_concurrent (a TEXT NOT NULL);",
+		"INSERT INTO test_concurrent (a) VALUES ('foo');",
+	})
+	defer removeTempDir(migrationsPath)
+
+	instances := []*SqlDb{}
+	for i := 0; i < 3; i++ {
+		db, err := InitSqlite(path)
+		if err != nil {
+			t.Fatalf("InitSqlite failed: %v", err)
+		}
+		defer db.Close()
+		instances = append(instances, db)
+	}
+
+	// when
+	var wg sync.WaitGroup
+	errs := make([]error, len(instances))
+	for i, db := range instances {
+		wg.Add(1)
+		go func(i int, db *SqlDb) {
+			defer wg.Done()
+			errs[i] = db.RunMigrations(migrationsPath)
+		}(i, db)
+	}
+	wg.Wait()
+
+	// then
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	var count int
+	err := instances[0].QueryRow("SELECT COUNT(*) FROM test_concurrent").Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,85 @@
+package sqldb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// MigrationOptions control how migration names are validated.
+// Migrations whose name starts with a numeric version, either sequential ("1_users.sql",
+// "0002_index.sql") or a timestamp ("20240131120000_users.sql"), run in numeric version order, so
+// "10_x.sql" runs after "9_y.sql" without zero padding. Migrations without a version, e.g.
+// "create_users.sql", run after them in name order. Enabling any check requires every migration
+// to have a version.
+type MigrationOptions struct {
+	// RejectDuplicateVersions rejects several migrations sharing a version; by default they are
+	// ordered by name.
+	RejectDuplicateVersions bool
+	// RequireSequentialVersions rejects gaps between consecutive versions.
+	RequireSequentialVersions bool
+}
+
+// SetMigrationOptions configures validation of migration names.
+// Should be called before the database is used concurrently.
+func (db *SqlDb) SetMigrationOptions(opts MigrationOptions) {
+	db.migrationOptions = opts
+}
+
+func (o MigrationOptions) strict() bool {
+	return o.RejectDuplicateVersions || o.RequireSequentialVersions
+}
+
+// order parses migration versions and sorts migrations in place, validating them against the options.
+func (o MigrationOptions) order(migrations []migration) error {
+	for i := range migrations {
+		version, err := migrationVersion(migrations[i].name)
+		if err != nil {
+			if o.strict() {
+				return err
+			}
+			continue
+		}
+		migrations[i].version = version
+		migrations[i].versioned = true
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		a, b := migrations[i], migrations[j]
+		if a.versioned != b.versioned {
+			return a.versioned
+		}
+		if a.version != b.version {
+			return a.version < b.version
+		}
+		return a.name < b.name
+	})
+
+	for i := 1; i < len(migrations); i++ {
+		prev, cur := migrations[i-1], migrations[i]
+		if prev.version == cur.version && o.RejectDuplicateVersions {
+			return fmt.Errorf("duplicate migration version %d: %s and %s", cur.version, prev.name, cur.name)
+		}
+		if cur.version > prev.version+1 && o.RequireSequentialVersions {
+			return fmt.Errorf("gap in migration versions between %s and %s", prev.name, cur.name)
+		}
+	}
+
+	return nil
+}
+
+func migrationVersion(name string) (uint64, error) {
+	end := 0
+	for end < len(name) && name[end] >= '0' && name[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, fmt.Errorf("migration name must start with a numeric version: %s", name)
+	}
+
+	version, err := strconv.ParseUint(name[:end], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid migration version in %s: %w", name, err)
+	}
+	return version, nil
+}
@@ -0,0 +1,69 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationOptions_OrderNumerically(t *testing.T) {
+	// given
+	migrations := namedMigrations("10_ten.sql", "2_two.sql", "1_one.sql", "0009_nine")
+
+	// when
+	err := MigrationOptions{}.order(migrations)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1_one.sql", "2_two.sql", "0009_nine", "10_ten.sql"}, migrationNames(migrations))
+}
+
+func TestMigrationOptions_Duplicates(t *testing.T) {
+	migrations := namedMigrations("1_b.sql", "1_a.sql")
+	assert.Error(t, MigrationOptions{RejectDuplicateVersions: true}.order(migrations))
+
+	err := MigrationOptions{}.order(migrations)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1_a.sql", "1_b.sql"}, migrationNames(migrations))
+}
+
+func TestMigrationOptions_Gaps(t *testing.T) {
+	assert.NoError(t, MigrationOptions{}.order(namedMigrations("1_a.sql", "3_c.sql")))
+	assert.Error(t, MigrationOptions{RequireSequentialVersions: true}.order(namedMigrations("1_a.sql", "3_c.sql")))
+	assert.NoError(t, MigrationOptions{RequireSequentialVersions: true}.order(namedMigrations("2_b.sql", "3_c.sql")))
+}
+
+func TestMigrationOptions_Unversioned(t *testing.T) {
+	// given
+	migrations := namedMigrations("create_users.sql", "10_ten.sql", "add_index.sql", "2_two.sql")
+
+	// when
+	err := MigrationOptions{}.order(migrations)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2_two.sql", "10_ten.sql", "add_index.sql", "create_users.sql"}, migrationNames(migrations))
+}
+
+func TestMigrationOptions_InvalidNames(t *testing.T) {
+	strict := MigrationOptions{RejectDuplicateVersions: true}
+	assert.Error(t, strict.order(namedMigrations("users.sql")))
+	assert.Error(t, strict.order(namedMigrations("99999999999999999999999_huge.sql")))
+	assert.NoError(t, strict.order(namedMigrations("20240131120000_users.sql")))
+}
+
+func namedMigrations(names ...string) []migration {
+	migrations := make([]migration, len(names))
+	for i, name := range names {
+		migrations[i] = migration{name: name}
+	}
+	return migrations
+}
+
+func migrationNames(migrations []migration) []string {
+	names := make([]string, len(migrations))
+	for i, m := range migrations {
+		names[i] = m.name
+	}
+	return names
+}
@@ -0,0 +1,127 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+type AppliedMigration struct {
+	File      string
+	Md5       string
+	AppliedAt time.Time
+}
+
+type MigrationStatus struct {
+	Applied []AppliedMigration
+	// Pending holds names of migration files and Go migrations whose checksum is not recorded in the migrations table.
+	Pending []string
+}
+
+// MigrationStatus reports applied migrations and files in migrationsPath that are not applied yet.
+func (db *SqlDb) MigrationStatus(migrationsPath string) (*MigrationStatus, error) {
+	return db.MigrationStatusCtx(context.Background(), migrationsPath)
+}
+
+func (db *SqlDb) MigrationStatusCtx(ctx context.Context, migrationsPath string) (*MigrationStatus, error) {
+	return db.MigrationStatusFSCtx(ctx, os.DirFS(migrationsPath), ".")
+}
+
+// MigrationStatusFS is the fs.FS counterpart of MigrationStatus, see RunMigrationsFS.
+func (db *SqlDb) MigrationStatusFS(fsys fs.FS, root string) (*MigrationStatus, error) {
+	return db.MigrationStatusFSCtx(context.Background(), fsys, root)
+}
+
+// MigrationStatusFSCtx only reads the database, so it works on a read-only handle; before the
+// first migrations run every migration is reported as pending.
+func (db *SqlDb) MigrationStatusFSCtx(ctx context.Context, fsys fs.FS, root string) (*MigrationStatus, error) {
+	var tables int
+	err := db.QueryRowCtx(ctx, db.dialect.tableExistsQuery(), "migrations").Scan(&tables)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []AppliedMigration{}
+	if tables > 0 {
+		applied, err = db.appliedMigrations(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	appliedMd5 := make(map[string]bool, len(applied))
+	for _, m := range applied {
+		appliedMd5[m.Md5] = true
+	}
+
+	migrations, err := db.loadMigrations(fsys, root, true)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{
+		Applied: applied,
+		Pending: []string{},
+	}
+	for _, m := range migrations {
+		if !appliedMd5[m.md5] {
+			status.Pending = append(status.Pending, m.name)
+		}
+	}
+
+	return status, nil
+}
+
+func (db *SqlDb) appliedMigrations(ctx context.Context) ([]AppliedMigration, error) {
+	rows, err := db.QueryCtx(ctx, "SELECT file, md5, applied_at FROM migrations ORDER BY applied_at, file")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := []AppliedMigration{}
+	for rows.Next() {
+		var m AppliedMigration
+		var appliedAt any
+		err = rows.Scan(&m.File, &m.Md5, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		m.AppliedAt, err = parseTimestamp(appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		applied = append(applied, m)
+	}
+
+	return applied, rows.Err()
+}
+
+// parseTimestamp converts a scanned timestamp column into time.Time.
+// Drivers return either time.Time or the textual representation, depending on the column type.
+func parseTimestamp(value any) (time.Time, error) {
+	var s string
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return time.Time{}, fmt.Errorf("unsupported timestamp value: %v", value)
+	}
+
+	for _, layout := range sqlite3.SQLiteTimestampFormats {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported timestamp format: %s", s)
+}
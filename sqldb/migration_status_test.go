@@ -0,0 +1,95 @@
+package sqldb
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationStatus(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	script1 := "CREATE TABLE test_migration_status (a TEXT NOT NULL);"
+	script2 := "INSERT INTO test_migration_status (a) VALUES ('foo');"
+
+	path := setupMigrationFiles([]string{script1})
+	defer removeTempDir(path)
+
+	before := time.Now().Add(-time.Second)
+	err = db.RunMigrations(path)
+	if err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+	setupMigrationFile(path, 1, script2)
+
+	// when
+	status, err := db.MigrationStatus(path)
+
+	// then
+	assert.NoError(t, err)
+	if assert.Len(t, status.Applied, 1) {
+		assert.Equal(t, "0.sql", status.Applied[0].File)
+		assert.Equal(t, migrationMd5([]byte(script1)), status.Applied[0].Md5)
+		assert.True(t, status.Applied[0].AppliedAt.After(before))
+	}
+	assert.Equal(t, []string{"1.sql"}, status.Pending)
+}
+
+func TestMigrationStatus_EmptyDatabase(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	path := setupMigrationFiles([]string{"SELECT 1;"})
+	defer removeTempDir(path)
+
+	// when
+	status, err := db.MigrationStatus(path)
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, status.Applied)
+	assert.Equal(t, []string{"0.sql"}, status.Pending)
+	var tables int
+	assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'migrations'").Scan(&tables))
+	assert.Zero(t, tables, "status doesn't create the migrations table")
+}
+
+func TestMigrationStatus_ReadOnly(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "status.db")
+	db, err := InitSqlite(path)
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	assert.NoError(t, db.Ping())
+	assert.NoError(t, db.OpenReader(path))
+	reader, _ := db.Reader()
+	fsys := fstest.MapFS{"1_create.sql": {Data: []byte("CREATE TABLE test_status_ro (a TEXT);")}}
+
+	// when
+	before, beforeErr := reader.MigrationStatusFS(fsys, ".")
+	assert.NoError(t, db.RunMigrationsFS(fsys, "."))
+	after, afterErr := reader.MigrationStatusFS(fsys, ".")
+
+	// then
+	assert.NoError(t, beforeErr)
+	assert.Equal(t, []string{"1_create.sql"}, before.Pending)
+	assert.NoError(t, afterErr)
+	assert.Len(t, after.Applied, 1)
+	assert.Empty(t, after.Pending)
+}
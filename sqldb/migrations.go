@@ -1,12 +1,15 @@
 package sqldb
 
 import (
+	"context"
 	"crypto/md5"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
-	"path/filepath"
+	"path"
 	"sort"
 	"time"
 )
@@ -20,50 +23,210 @@ CREATE TABLE IF NOT EXISTS migrations (
 );
 `
 
+const migrationsInitialScriptMySQL = `
+CREATE TABLE IF NOT EXISTS migrations (
+    file VARCHAR(255) NOT NULL,
+    md5 CHAR(32) NOT NULL,
+    applied_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (md5)
+);
+`
+
 func (db *SqlDb) RunMigrations(migrationsPath string) error {
+	return db.RunMigrationsCtx(context.Background(), migrationsPath)
+}
+
+func (db *SqlDb) RunMigrationsCtx(ctx context.Context, migrationsPath string) error {
 	log.Println("Running migrations from: ", migrationsPath)
-	files, err := filepath.Glob(filepath.Join(migrationsPath, "*.sql"))
+	return db.runMigrations(ctx, os.DirFS(migrationsPath), ".", true)
+}
+
+// RunMigrationsFS runs *.sql migrations located directly in root of fsys.
+// Works with embed.FS, os.DirFS, fstest.MapFS and any other fs.FS implementation.
+func (db *SqlDb) RunMigrationsFS(fsys fs.FS, root string) error {
+	return db.RunMigrationsFSCtx(context.Background(), fsys, root)
+}
+
+func (db *SqlDb) RunMigrationsFSCtx(ctx context.Context, fsys fs.FS, root string) error {
+	log.Println("Running migrations from fs: ", root)
+	return db.runMigrations(ctx, fsys, root, true)
+}
+
+// RunPackageMigrationsFS runs *.sql migrations owned by a package, e.g. the schema of
+// conversations.Store. They share the migrations table with the application's migrations, but
+// registered Go migrations, MigrationOptions and the latest migration checked by HealthCheck only
+// apply to the application's own runs.
+func (db *SqlDb) RunPackageMigrationsFS(fsys fs.FS, root string) error {
+	return db.RunPackageMigrationsFSCtx(context.Background(), fsys, root)
+}
+
+func (db *SqlDb) RunPackageMigrationsFSCtx(ctx context.Context, fsys fs.FS, root string) error {
+	log.Println("Running package migrations from fs: ", root)
+	return db.runMigrations(ctx, fsys, root, false)
+}
+
+// runMigrations applies migrations from root of fsys. app tells the application's migrations,
+// merged with registered Go migrations, from migrations owned by a package.
+func (db *SqlDb) runMigrations(ctx context.Context, fsys fs.FS, root string, app bool) (err error) {
+	migrations, err := db.loadMigrations(fsys, root, app)
 	if err != nil {
 		return err
 	}
 
-	sort.Strings(files)
+	err = db.applyMigration(ctx, db, db.dialect.migrationsInitialScript())
+	if err != nil {
+		return err
+	}
 
-	db.applyMigration(migrationsInitialScript)
+	unlock, err := db.lockMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to release migrations lock: %w", unlockErr))
+		}
+	}()
 
-	for _, file := range files {
-		fileName := filepath.Base(file)
-		contents, err := os.ReadFile(file)
+	latestMd5 := ""
+	for _, m := range migrations {
+		log.Println("Migration applying: ", m.name)
+		latestMd5 = m.md5
+		applied, err := db.checkIfMigrationPreviouslyApplied(ctx, m.md5)
 		if err != nil {
 			return err
 		}
-		log.Println("Migration applying: ", file)
-		nowMd5 := fmt.Sprintf("%x", md5.Sum(contents))
-		applied, err := db.checkIfMigrationPreviouslyApplied(nowMd5)
-		if err != nil {
-			return err
+		if applied {
+			log.Println("Migration already applied: ", m.name)
+			continue
 		}
-		if !applied {
-			err = db.applyMigration(string(contents))
-			if err != nil {
-				return err
-			}
-			err = db.saveMigrationInfo(fileName, nowMd5)
-			if err != nil {
-				return err
-			}
+		if m.fn != nil {
+			err = db.applyAndSaveGoMigration(ctx, m.name, m.md5, m.fn)
 		} else {
-			log.Println("Migration already applied: ", file)
-			continue
+			err = db.applyAndSaveMigration(ctx, m.name, m.md5, m.script)
+		}
+		if err != nil {
+			return err
 		}
-		log.Println("Migration applied: ", file)
+		log.Println("Migration applied: ", m.name)
+	}
+
+	// HealthCheck watches the application's schema, not the ones of packages
+	if app && latestMd5 != "" {
+		db.latestMigration.Store(latestMd5)
 	}
 
 	return nil
 }
 
-func (db *SqlDb) applyMigration(migration string) error {
-	_, err := db.Exec(migration)
+// migration is either a SQL file or a registered Go function.
+type migration struct {
+	name      string
+	version   uint64
+	versioned bool
+	md5       string
+	script    string
+	fn        GoMigration
+}
+
+// loadMigrations returns SQL files from root of fsys ordered by version, see MigrationOptions.
+// The application's migrations include registered Go migrations and follow its MigrationOptions.
+func (db *SqlDb) loadMigrations(fsys fs.FS, root string, app bool) ([]migration, error) {
+	files, err := migrationFiles(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := []migration{}
+	names := map[string]bool{}
+	for _, file := range files {
+		contents, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		name := path.Base(file)
+		names[name] = true
+		migrations = append(migrations, migration{
+			name:   name,
+			md5:    migrationMd5(contents),
+			script: string(contents),
+		})
+	}
+
+	if !app {
+		err = MigrationOptions{}.order(migrations)
+		if err != nil {
+			return nil, err
+		}
+		return migrations, nil
+	}
+
+	db.goMigrationsMu.Lock()
+	defer db.goMigrationsMu.Unlock()
+	for name, fn := range db.goMigrations {
+		if names[name] {
+			return nil, fmt.Errorf("go migration conflicts with migration file: %s", name)
+		}
+		migrations = append(migrations, migration{
+			name: name,
+			md5:  goMigrationMd5(name),
+			fn:   fn,
+		})
+	}
+
+	err = db.migrationOptions.order(migrations)
+	if err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
+
+func migrationFiles(fsys fs.FS, root string) ([]string, error) {
+	files, err := fs.Glob(fsys, path.Join(root, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func migrationMd5(contents []byte) string {
+	return fmt.Sprintf("%x", md5.Sum(contents))
+}
+
+// applyAndSaveMigration applies a migration and records it in the migrations table.
+// Both happen in a single transaction when the dialect supports transactional DDL,
+// so a failing statement doesn't leave the schema half-applied.
+func (db *SqlDb) applyAndSaveMigration(ctx context.Context, file string, md5 string, migration string) error {
+	if !db.dialect.transactionalDDL() {
+		err := db.applyMigration(ctx, db, migration)
+		if err != nil {
+			return err
+		}
+		return db.saveMigrationInfo(ctx, db, file, md5)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = db.applyMigration(ctx, tx, migration)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = db.saveMigrationInfo(ctx, tx, file, md5)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *SqlDb) applyMigration(ctx context.Context, ex execer, migration string) error {
+	err := db.dialect.execScript(ctx, ex, migration)
 	if err != nil {
 		log.Println("Error applying migration: ", migration)
 		return err
@@ -72,8 +235,8 @@ func (db *SqlDb) applyMigration(migration string) error {
 	return nil
 }
 
-func (db *SqlDb) checkIfMigrationPreviouslyApplied(nowMd5 string) (bool, error) {
-	row := db.QueryRow("SELECT file FROM migrations WHERE md5 = $1", nowMd5)
+func (db *SqlDb) checkIfMigrationPreviouslyApplied(ctx context.Context, nowMd5 string) (bool, error) {
+	row := db.QueryRowCtx(ctx, "SELECT file FROM migrations WHERE md5 = $1", nowMd5)
 	var file string
 	err := row.Scan(&file)
 	if err == sql.ErrNoRows {
@@ -84,8 +247,9 @@ func (db *SqlDb) checkIfMigrationPreviouslyApplied(nowMd5 string) (bool, error)
 	return true, nil
 }
 
-func (db *SqlDb) saveMigrationInfo(file string, md5 string) error {
-	_, err := db.Exec("INSERT INTO migrations (file, md5, applied_at) VALUES ($1, $2, $3)", file, md5, time.Now())
+func (db *SqlDb) saveMigrationInfo(ctx context.Context, ex execer, file string, md5 string) error {
+	query, args := db.dialect.rebind("INSERT INTO migrations (file, md5, applied_at) VALUES ($1, $2, $3)", []any{file, md5, time.Now()})
+	_, err := ex.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
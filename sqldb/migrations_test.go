@@ -1,11 +1,14 @@
 package sqldb
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os" // Add the os package
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -66,11 +69,15 @@ func TestRunMigrations_Success(t *testing.T) {
 func setupMigrationFiles(files []string) (path string) {
 	path = createTempDir()
 	for i, file := range files {
-		os.WriteFile(fmt.Sprintf("%s/%d.sql", path, i), []byte(file), 0644)
+		setupMigrationFile(path, i, file)
 	}
 	return
 }
 
+func setupMigrationFile(path string, i int, file string) {
+	os.WriteFile(fmt.Sprintf("%s/%d.sql", path, i), []byte(file), 0644)
+}
+
 func createTempDir() string {
 	dir, err := os.MkdirTemp("", "test")
 	if err != nil {
@@ -85,3 +92,228 @@ func removeTempDir(path string) {
 		log.Fatal(err)
 	}
 }
+
+func TestRunMigrations_FailingMigrationIsRolledBack(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	script := `
+	CREATE TABLE test_migration_rollback (a TEXT NOT NULL);
+	INSERT INTO missing_table (a) VALUES ('foo');
+	`
+
+	path := setupMigrationFiles([]string{script})
+	defer removeTempDir(path)
+
+	// when
+	err = db.RunMigrations(path)
+
+	// then
+	assert.Error(t, err)
+
+	var tableCount int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'test_migration_rollback'").Scan(&tableCount)
+	if err != nil {
+		t.Fatalf("Failed to query sqlite_master: %v", err)
+	}
+	assert.Equal(t, 0, tableCount, "test_migration_rollback should not exist after rollback")
+
+	var migrationCount int
+	err = db.QueryRow("SELECT COUNT(*) FROM migrations").Scan(&migrationCount)
+	if err != nil {
+		t.Fatalf("Failed to count migrations: %v", err)
+	}
+	assert.Equal(t, 0, migrationCount)
+}
+
+func TestRunMigrationsFS_Success(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	fsys := fstest.MapFS{
+		"migrations/0.sql":        {Data: []byte("CREATE TABLE test_migration_fs (a TEXT NOT NULL);")},
+		"migrations/1.sql":        {Data: []byte("INSERT INTO test_migration_fs (a) VALUES ('foo');")},
+		"migrations/README.md":    {Data: []byte("not a migration")},
+		"migrations/nested/2.sql": {Data: []byte("INSERT INTO test_migration_fs (a) VALUES ('nested');")},
+	}
+
+	// when
+	err = db.RunMigrationsFS(fsys, "migrations")
+
+	// then
+	assert.NoError(t, err)
+
+	var a string
+	err = db.QueryRow("SELECT a FROM test_migration_fs").Scan(&a)
+	if err != nil {
+		t.Fatalf("Failed to query test_migration_fs: %v", err)
+	}
+	assert.Equal(t, "foo", a)
+
+	var migrationCount int
+	err = db.QueryRow("SELECT COUNT(*) FROM migrations").Scan(&migrationCount)
+	if err != nil {
+		t.Fatalf("Failed to count migrations: %v", err)
+	}
+	assert.Equal(t, 2, migrationCount)
+}
+
+func TestRunMigrations_GoMigrations(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	fsys := fstest.MapFS{
+		"0_create.sql": {Data: []byte("CREATE TABLE test_go_migration (a TEXT NOT NULL);")},
+		"2_insert.sql": {Data: []byte("INSERT INTO test_go_migration (a) VALUES ('sql');")},
+	}
+	calls := 0
+	err = db.RegisterGoMigration("1_backfill", func(ctx context.Context, tx *sql.Tx) error {
+		calls++
+		_, err := tx.ExecContext(ctx, "INSERT INTO test_go_migration (a) VALUES ('go')")
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Error(t, db.RegisterGoMigration("1_backfill", nil), "duplicate name")
+
+	// when
+	assert.NoError(t, db.RunMigrationsFS(fsys, "."))
+	assert.NoError(t, db.RunMigrationsFS(fsys, "."))
+
+	// then
+	assert.Equal(t, 1, calls)
+
+	rows, err := db.Query("SELECT a FROM test_go_migration ORDER BY rowid")
+	if err != nil {
+		t.Fatalf("Failed to query test_go_migration: %v", err)
+	}
+	defer rows.Close()
+	values := []string{}
+	for rows.Next() {
+		var a string
+		rows.Scan(&a)
+		values = append(values, a)
+	}
+	assert.Equal(t, []string{"go", "sql"}, values)
+}
+
+func TestRunPackageMigrations_SkipsGoMigrationsAndOptions(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.SetMigrationOptions(MigrationOptions{RequireSequentialVersions: true})
+
+	calls := 0
+	db.RegisterGoMigration("5_backfill", func(ctx context.Context, tx *sql.Tx) error {
+		calls++
+		return nil
+	})
+	pkg := fstest.MapFS{
+		"1_pkg.sql": {Data: []byte("CREATE TABLE test_pkg (a TEXT);")},
+		"3_pkg.sql": {Data: []byte("CREATE INDEX test_pkg_a ON test_pkg (a);")},
+	}
+
+	// when
+	err = db.RunPackageMigrationsFS(pkg, ".")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 0, calls)
+	status, err := db.MigrationStatusFS(fstest.MapFS{}, ".")
+	assert.NoError(t, err)
+	assert.Len(t, status.Applied, 2)
+	assert.Equal(t, []string{"5_backfill"}, status.Pending)
+}
+
+func TestRunMigrations_FailingGoMigrationIsRolledBack(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	db.RegisterGoMigration("0_failing", func(ctx context.Context, tx *sql.Tx) error {
+		return errors.New("boom")
+	})
+
+	// when
+	err = db.RunMigrationsFS(fstest.MapFS{}, ".")
+
+	// then
+	assert.EqualError(t, err, "boom")
+	status, err := db.MigrationStatusFS(fstest.MapFS{}, ".")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0_failing"}, status.Pending)
+}
+
+func TestRunMigrations_NumericOrder(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	scripts := []string{"CREATE TABLE test_numeric_order (n INT NOT NULL);"}
+	for i := 1; i <= 10; i++ {
+		scripts = append(scripts, fmt.Sprintf("INSERT INTO test_numeric_order (n) VALUES (%d);", i))
+	}
+	path := setupMigrationFiles(scripts)
+	defer removeTempDir(path)
+
+	// when
+	err = db.RunMigrations(path)
+
+	// then
+	assert.NoError(t, err)
+	var last int
+	err = db.QueryRow("SELECT n FROM test_numeric_order ORDER BY rowid DESC LIMIT 1").Scan(&last)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, last)
+}
+
+func TestRunMigrations_UnversionedNames(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	fsys := fstest.MapFS{
+		"a_create.sql": {Data: []byte("CREATE TABLE test_unversioned (a TEXT);")},
+		"b_insert.sql": {Data: []byte("INSERT INTO test_unversioned (a) VALUES ('x');")},
+	}
+	assert.NoError(t, db.RunMigrationsFS(fsys, "."))
+
+	// when
+	err = db.RunMigrationsFS(fsys, ".")
+
+	// then
+	assert.NoError(t, err)
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM test_unversioned").Scan(&count))
+	assert.Equal(t, 1, count)
+}
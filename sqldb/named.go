@@ -0,0 +1,160 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NamedExecCtx executes a query with :name parameters taken from arg, which is either
+// a struct with db tags (or a pointer to one) or a map[string]any.
+func (db *SqlDb) NamedExecCtx(ctx context.Context, query string, arg any) (sql.Result, error) {
+	positional, args, err := db.dialect.bindNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecCtx(ctx, positional, args...)
+}
+
+// NamedQueryCtx runs a query with :name parameters, see NamedExecCtx.
+func (db *SqlDb) NamedQueryCtx(ctx context.Context, query string, arg any) (*sql.Rows, error) {
+	positional, args, err := db.dialect.bindNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return db.QueryCtx(ctx, positional, args...)
+}
+
+// SelectStructs runs a query and scans every row into T by column names, see StructScan.
+func SelectStructs[T any](ctx context.Context, db *SqlDb, query string, args ...any) ([]T, error) {
+	rows, err := db.QueryCtx(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []T{}
+	for rows.Next() {
+		var item T
+		err = StructScan(rows, &item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	return result, rows.Err()
+}
+
+// StructScan scans the current row into a struct pointer, matching columns to db tags.
+// Every column of the result must have a matching field.
+func StructScan(rows *sql.Rows, dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("expected non-nil pointer to struct, got: %T", dest)
+	}
+	v = v.Elem()
+
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return err
+	}
+	byColumn := make(map[string]structField, len(fields))
+	for _, f := range fields {
+		byColumn[f.column] = f
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	targets := make([]any, len(columns))
+	for i, column := range columns {
+		f, ok := byColumn[column]
+		if !ok {
+			return fmt.Errorf("no field for column %s in: %s", column, v.Type())
+		}
+		targets[i] = v.FieldByIndex(f.index).Addr().Interface()
+	}
+
+	return rows.Scan(targets...)
+}
+
+// bindNamed rewrites :name parameters into $N placeholders and collects their values.
+// Every occurrence gets its own placeholder, so the query stays valid after rebinding to ?.
+// String literals, quoted identifiers, comments and :: casts are left untouched.
+func (d Dialect) bindNamed(query string, arg any) (string, []any, error) {
+	lookup, err := namedValues(arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+	args := []any{}
+	for i := 0; i < len(query); i++ {
+		if end, ok := d.skipNonCode(query, i); ok {
+			sb.WriteString(query[i : end+1])
+			i = end
+			continue
+		}
+
+		c := query[i]
+		switch {
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			sb.WriteString("::")
+			i++
+		case c == ':' && i+1 < len(query) && isWordChar(query[i+1]):
+			end := i + 1
+			for end < len(query) && isWordChar(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			value, ok := lookup(name)
+			if !ok {
+				return "", nil, fmt.Errorf("missing value for parameter: %s", name)
+			}
+			args = append(args, value)
+			fmt.Fprintf(&sb, "$%d", len(args))
+			i = end - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), args, nil
+}
+
+func namedValues(arg any) (func(name string) (any, bool), error) {
+	if m, ok := arg.(map[string]any); ok {
+		return func(name string) (any, bool) {
+			value, ok := m[name]
+			return value, ok
+		}, nil
+	}
+
+	v := reflect.ValueOf(arg)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil, fmt.Errorf("expected struct or map[string]any for named parameters, got: %T", arg)
+	}
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	byColumn := make(map[string]structField, len(fields))
+	for _, f := range fields {
+		byColumn[f.column] = f
+	}
+
+	return func(name string) (any, bool) {
+		f, ok := byColumn[name]
+		if !ok {
+			return nil, false
+		}
+		return v.FieldByIndex(f.index).Interface(), true
+	}, nil
+}
@@ -0,0 +1,87 @@
+package sqldb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namedTestUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+	Age  int    `db:"age"`
+}
+
+func TestBindNamed(t *testing.T) {
+	query, args, err := DialectSqlite.bindNamed(
+		"SELECT * FROM users WHERE name = :name AND note != ':not_a_param' AND age > :age AND age::text != :age",
+		map[string]any{"name": "alice", "age": 30},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE name = $1 AND note != ':not_a_param' AND age > $2 AND age::text != $3", query)
+	assert.Equal(t, []any{"alice", 30, 30}, args)
+
+	_, _, err = DialectSqlite.bindNamed("SELECT :missing", map[string]any{})
+	assert.Error(t, err)
+}
+
+func TestBindNamed_SkipsComments(t *testing.T) {
+	query, args, err := DialectSqlite.bindNamed(
+		"SELECT * FROM users -- filter by :name\nWHERE /* :age */ name = :name",
+		map[string]any{"name": "alice"},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users -- filter by :name\nWHERE /* :age */ name = $1", query)
+	assert.Equal(t, []any{"alice"}, args)
+}
+
+func TestBindNamed_NilArg(t *testing.T) {
+	_, _, err := DialectSqlite.bindNamed("SELECT :name", nil)
+	assert.Error(t, err)
+
+	_, _, err = DialectSqlite.bindNamed("SELECT :name", (*namedTestUser)(nil))
+	assert.Error(t, err)
+}
+
+func TestNamedExecAndStructScan(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	_, err = db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, age INT NOT NULL)")
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	// when
+	_, err = db.NamedExecCtx(ctx, "INSERT INTO users (id, name, age) VALUES (:id, :name, :age)", &namedTestUser{ID: 1, Name: "alice", Age: 30})
+	assert.NoError(t, err)
+	_, err = db.NamedExecCtx(ctx, "INSERT INTO users (id, name, age) VALUES (:id, :name, :age)", namedTestUser{ID: 2, Name: "bob", Age: 25})
+	assert.NoError(t, err)
+
+	// then
+	users, err := SelectStructs[namedTestUser](ctx, db, "SELECT id, name, age FROM users WHERE age > $1 ORDER BY id", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []namedTestUser{{1, "alice", 30}, {2, "bob", 25}}, users)
+
+	rows, err := db.NamedQueryCtx(ctx, "SELECT name, age FROM users WHERE name = :name", map[string]any{"name": "bob"})
+	if err != nil {
+		t.Fatalf("NamedQueryCtx failed: %v", err)
+	}
+	assert.True(t, rows.Next())
+	var bob namedTestUser
+	assert.NoError(t, StructScan(rows, &bob))
+	assert.Equal(t, namedTestUser{Name: "bob", Age: 25}, bob)
+	rows.Close()
+
+	_, err = SelectStructs[namedTestUser](ctx, db, "SELECT id, name, age, 1 AS extra FROM users")
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// QueryEvent describes a statement executed through ExecCtx, QueryCtx or QueryRowCtx.
+type QueryEvent struct {
+	Query string
+	// Args are redacted: strings and byte slices are replaced with their length.
+	Args     []any
+	Duration time.Duration
+	Err      error
+	// Slow is set when Duration exceeds the threshold passed to SetQueryHook.
+	Slow bool
+}
+
+type QueryHook func(ctx context.Context, event QueryEvent)
+
+// SetQueryHook installs a hook called after every statement executed through the Ctx helpers.
+// A zero slowThreshold never flags queries as slow. Pass nil hook to disable.
+// Should be called before the database is used concurrently.
+func (db *SqlDb) SetQueryHook(hook QueryHook, slowThreshold time.Duration) {
+	db.queryHook = hook
+	db.slowQueryThreshold = slowThreshold
+}
+
+// LogQueryHook logs slow and failed queries with the standard logger.
+func LogQueryHook(ctx context.Context, event QueryEvent) {
+	if event.Err != nil && event.Err != sql.ErrNoRows {
+		log.Println("Query failed: ", event.Query, event.Args, event.Duration, event.Err)
+	} else if event.Slow {
+		log.Println("Slow query: ", event.Query, event.Args, event.Duration)
+	}
+}
+
+func (db *SqlDb) observeQuery(ctx context.Context, query string, args []any, start time.Time, err error) {
+	if db.queryHook == nil && db.metrics == nil {
+		return
+	}
+
+	duration := time.Since(start)
+	if db.metrics != nil {
+		db.metrics.QueryDone(queryOperation(query), duration, err)
+	}
+	if db.queryHook == nil {
+		return
+	}
+	db.queryHook(ctx, QueryEvent{
+		Query:    query,
+		Args:     redactArgs(args),
+		Duration: duration,
+		Err:      err,
+		Slow:     db.slowQueryThreshold > 0 && duration > db.slowQueryThreshold,
+	})
+}
+
+func redactArgs(args []any) []any {
+	redacted := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			redacted[i] = fmt.Sprintf("<redacted %d chars>", len(v))
+		case []byte:
+			redacted[i] = fmt.Sprintf("<redacted %d bytes>", len(v))
+		default:
+			redacted[i] = arg
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,92 @@
+package sqldb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryHook(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	events := []QueryEvent{}
+	db.SetQueryHook(func(ctx context.Context, event QueryEvent) {
+		events = append(events, event)
+	}, time.Nanosecond)
+
+	// when
+	db.ExecCtx(ctx, "CREATE TABLE test_hook (a TEXT, b INT)")
+	db.ExecCtx(ctx, "INSERT INTO test_hook (a, b) VALUES ($1, $2)", "secret", 42)
+	db.QueryRowCtx(ctx, "SELECT missing FROM test_hook")
+
+	// then
+	if !assert.Len(t, events, 3) {
+		return
+	}
+	assert.Equal(t, "INSERT INTO test_hook (a, b) VALUES ($1, $2)", events[1].Query)
+	assert.Equal(t, []any{"<redacted 6 chars>", 42}, events[1].Args)
+	assert.NoError(t, events[1].Err)
+	assert.True(t, events[1].Slow)
+	assert.Error(t, events[2].Err)
+}
+
+func TestQueryHook_WithTx(t *testing.T) {
+	// given
+	db := setupTxDb(t)
+	ctx := context.Background()
+
+	queries := []string{}
+	db.SetQueryHook(func(ctx context.Context, event QueryEvent) {
+		queries = append(queries, event.Query)
+	}, 0)
+
+	// when
+	err := db.WithTx(ctx, func(ctx context.Context, tx *Tx) error {
+		_, err := tx.ExecCtx(ctx, "INSERT INTO test_tx (a) VALUES ($1)", "in tx")
+		if err != nil {
+			return err
+		}
+		var count int
+		err = tx.QueryRowCtx(ctx, "SELECT COUNT(*) FROM test_tx").Scan(&count)
+		if err != nil {
+			return err
+		}
+		rows, err := tx.QueryCtx(ctx, "SELECT a FROM test_tx")
+		if err != nil {
+			return err
+		}
+		return rows.Close()
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"INSERT INTO test_tx (a) VALUES ($1)",
+		"SELECT COUNT(*) FROM test_tx",
+		"SELECT a FROM test_tx",
+	}, queries)
+}
+
+func TestQueryHook_Disabled(t *testing.T) {
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+
+	called := false
+	db.SetQueryHook(func(ctx context.Context, event QueryEvent) { called = true }, 0)
+	db.SetQueryHook(nil, 0)
+
+	db.ExecCtx(context.Background(), "SELECT 1")
+	assert.False(t, called)
+}
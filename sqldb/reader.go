@@ -0,0 +1,71 @@
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoReader is returned by Reader when OpenReader was not called.
+var ErrNoReader = errors.New("reader is not open")
+
+// OpenReader opens a read-only handle returned by Reader, so reporting queries can't mutate state.
+// For sqlite dsn is the database path, opened with mode=ro and query_only enabled and the
+// SqliteOptions of db.
+// For MySQL dsn usually points to a read replica; use a user with read-only grants.
+func (db *SqlDb) OpenReader(dsn string) error {
+	if db.reader != nil {
+		return errors.New("reader is already open")
+	}
+
+	var reader *SqlDb
+	var err error
+	switch db.dialect {
+	case DialectSqlite:
+		if strings.Contains(dsn, ":memory:") || strings.Contains(dsn, "mode=memory") {
+			return errors.New("in-memory sqlite database can't have a separate reader")
+		}
+		if !strings.HasPrefix(dsn, "file:") {
+			dsn = "file:" + dsn
+		}
+		dsn = withSqliteParam(dsn, "mode", "ro")
+		dsn = withSqliteParam(dsn, "_query_only", "true")
+		reader, err = InitSqliteWithOptions(dsn, db.sqliteOptions)
+	case DialectMySQL:
+		reader, err = InitMySQL(dsn)
+	default:
+		return fmt.Errorf("reader is not supported for dialect: %s", db.dialect)
+	}
+	if err != nil {
+		return err
+	}
+
+	db.reader = reader
+	return nil
+}
+
+// Reader returns the read-only handle opened with OpenReader, or ErrNoReader when there is none.
+func (db *SqlDb) Reader() (*SqlDb, error) {
+	if db.reader == nil {
+		return nil, ErrNoReader
+	}
+	return db.reader, nil
+}
+
+// ReaderOrPrimary returns the read-only handle opened with OpenReader, or db itself when there is
+// none. Unlike Reader, the result may be writable.
+func (db *SqlDb) ReaderOrPrimary() *SqlDb {
+	if db.reader == nil {
+		return db
+	}
+	return db.reader
+}
+
+// Close closes the database and its reader, if any.
+func (db *SqlDb) Close() error {
+	var readerErr error
+	if db.reader != nil {
+		readerErr = db.reader.Close()
+	}
+	return errors.Join(db.DB.Close(), readerErr)
+}
@@ -0,0 +1,79 @@
+package sqldb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReader(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "reader.db")
+	db, err := InitSqlite(path)
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	_, err = db.Reader()
+	assert.ErrorIs(t, err, ErrNoReader)
+	assert.Same(t, db, db.ReaderOrPrimary())
+
+	_, err = db.Exec("CREATE TABLE test_reader (a TEXT); INSERT INTO test_reader (a) VALUES ('foo');")
+	if err != nil {
+		t.Fatalf("Failed to prepare database: %v", err)
+	}
+
+	// when
+	err = db.OpenReader(path)
+
+	// then
+	assert.NoError(t, err)
+	reader, err := db.Reader()
+	assert.NoError(t, err)
+	assert.NotSame(t, db, reader)
+	assert.Same(t, reader, db.ReaderOrPrimary())
+
+	var a string
+	err = reader.QueryRow("SELECT a FROM test_reader").Scan(&a)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", a)
+
+	_, err = reader.Exec("INSERT INTO test_reader (a) VALUES ('bar')")
+	assert.Error(t, err)
+
+	assert.Error(t, db.OpenReader(path), "reader is already open")
+}
+
+func TestOpenReader_UsesPrimaryOptions(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "reader.db")
+	db, err := InitSqliteWithOptions(path, SqliteOptions{ForeignKeys: true, CacheSize: -4096})
+	if err != nil {
+		t.Fatalf("InitSqliteWithOptions failed: %v", err)
+	}
+	defer db.Close()
+	assert.NoError(t, db.Ping())
+
+	// when
+	err = db.OpenReader(path)
+
+	// then
+	assert.NoError(t, err)
+	reader, _ := db.Reader()
+	var foreignKeys, cacheSize int
+	assert.NoError(t, reader.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys))
+	assert.NoError(t, reader.QueryRow("PRAGMA cache_size").Scan(&cacheSize))
+	assert.Equal(t, 1, foreignKeys)
+	assert.Equal(t, -4096, cacheSize)
+}
+
+func TestReader_InMemory(t *testing.T) {
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+
+	assert.Error(t, db.OpenReader(":memory:"))
+}
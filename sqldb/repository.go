@@ -0,0 +1,198 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+)
+
+var ErrRecordNotFound = errs.New(errs.NotFound, "record not found")
+
+// Repository provides CRUD operations for a struct type mapped to a table with db tags:
+//
+//	type User struct {
+//		ID   int64  `db:"id,pk,auto"`
+//		Name string `db:"name"`
+//	}
+//
+// Exactly one field must be tagged pk. An auto primary key is skipped on insert and populated
+// from the generated id.
+type Repository[T any] struct {
+	db     *SqlDb
+	table  string
+	fields []structField
+	pk     structField
+}
+
+func NewRepository[T any](db *SqlDb, table string) (*Repository[T], error) {
+	fields, err := structFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &Repository[T]{
+		db:     db,
+		table:  table,
+		fields: fields,
+	}
+	pkCount := 0
+	for _, f := range fields {
+		if f.pk {
+			repo.pk = f
+			pkCount++
+		}
+	}
+	if pkCount != 1 {
+		return nil, fmt.Errorf("expected exactly one pk field, got: %d", pkCount)
+	}
+
+	return repo, nil
+}
+
+func (r *Repository[T]) Insert(ctx context.Context, entity *T) error {
+	v := reflect.ValueOf(entity).Elem()
+
+	columns := []string{}
+	args := []any{}
+	for _, f := range r.fields {
+		if f.auto {
+			continue
+		}
+		columns = append(columns, f.column)
+		args = append(args, v.FieldByIndex(f.index).Interface())
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", r.table, strings.Join(columns, ", "), placeholders(1, len(columns)))
+	result, err := r.db.ExecCtx(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if r.pk.auto {
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		pk := v.FieldByIndex(r.pk.index)
+		switch pk.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			pk.SetInt(id)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			pk.SetUint(uint64(id))
+		default:
+			return fmt.Errorf("auto pk field must be an integer, got: %s", pk.Type())
+		}
+	}
+
+	return nil
+}
+
+// Get returns the entity with the given primary key or ErrRecordNotFound.
+func (r *Repository[T]) Get(ctx context.Context, id any) (*T, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1", strings.Join(columnNames(r.fields), ", "), r.table, r.pk.column)
+	entity := new(T)
+	err := r.db.QueryRowCtx(ctx, query, id).Scan(r.scanTargets(entity)...)
+	if err == sql.ErrNoRows {
+		return nil, ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return entity, nil
+}
+
+// Update writes all non-pk fields of the entity. Returns ErrRecordNotFound if no row matched.
+func (r *Repository[T]) Update(ctx context.Context, entity *T) error {
+	v := reflect.ValueOf(entity).Elem()
+
+	sets := []string{}
+	args := []any{}
+	for _, f := range r.fields {
+		if f.pk {
+			continue
+		}
+		args = append(args, v.FieldByIndex(f.index).Interface())
+		sets = append(sets, fmt.Sprintf("%s = $%d", f.column, len(args)))
+	}
+	args = append(args, v.FieldByIndex(r.pk.index).Interface())
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d", r.table, strings.Join(sets, ", "), r.pk.column, len(args))
+	result, err := r.db.ExecCtx(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	err = expectAffected(result)
+	if err != ErrRecordNotFound {
+		return err
+	}
+	// MySQL counts changed rows, not matched ones, so rewriting the same values affects nothing
+	return r.expectExists(ctx, args[len(args)-1])
+}
+
+func (r *Repository[T]) expectExists(ctx context.Context, id any) error {
+	query := fmt.Sprintf("SELECT 1 FROM %s WHERE %s = $1", r.table, r.pk.column)
+	var one int
+	err := r.db.QueryRowCtx(ctx, query, id).Scan(&one)
+	if err == sql.ErrNoRows {
+		return ErrRecordNotFound
+	}
+	return err
+}
+
+// Delete removes the entity with the given primary key. Returns ErrRecordNotFound if no row matched.
+func (r *Repository[T]) Delete(ctx context.Context, id any) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1", r.table, r.pk.column)
+	result, err := r.db.ExecCtx(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+// List returns all entities ordered by primary key.
+func (r *Repository[T]) List(ctx context.Context) ([]T, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY %s", strings.Join(columnNames(r.fields), ", "), r.table, r.pk.column)
+	rows, err := r.db.QueryCtx(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := []T{}
+	for rows.Next() {
+		var entity T
+		err = rows.Scan(r.scanTargets(&entity)...)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+
+	return entities, rows.Err()
+}
+
+func (r *Repository[T]) scanTargets(entity *T) []any {
+	v := reflect.ValueOf(entity).Elem()
+	targets := make([]any, len(r.fields))
+	for i, f := range r.fields {
+		targets[i] = v.FieldByIndex(f.index).Addr().Interface()
+	}
+	return targets
+}
+
+func expectAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
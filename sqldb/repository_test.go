@@ -0,0 +1,114 @@
+package sqldb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+type repoTestUser struct {
+	ID      int64  `db:"id,pk,auto"`
+	Name    string `db:"name"`
+	Age     int    `db:"age"`
+	Ignored string
+}
+
+func TestRepository_CRUD(t *testing.T) {
+	// given
+	repo := setupRepository(t)
+	ctx := context.Background()
+	alice := &repoTestUser{Name: "alice", Age: 30, Ignored: "x"}
+	bob := &repoTestUser{Name: "bob", Age: 25}
+
+	// when
+	assert.NoError(t, repo.Insert(ctx, alice))
+	assert.NoError(t, repo.Insert(ctx, bob))
+
+	// then
+	assert.NotZero(t, alice.ID)
+	assert.NotEqual(t, alice.ID, bob.ID)
+
+	got, err := repo.Get(ctx, alice.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, &repoTestUser{ID: alice.ID, Name: "alice", Age: 30}, got)
+
+	alice.Age = 31
+	assert.NoError(t, repo.Update(ctx, alice))
+	got, err = repo.Get(ctx, alice.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 31, got.Age)
+
+	all, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, []string{all[0].Name, all[1].Name})
+
+	assert.NoError(t, repo.Delete(ctx, bob.ID))
+	_, err = repo.Get(ctx, bob.ID)
+	assert.ErrorIs(t, err, ErrRecordNotFound)
+	assert.ErrorIs(t, err, errs.NotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, bob.ID), ErrRecordNotFound)
+	assert.ErrorIs(t, repo.Update(ctx, bob), ErrRecordNotFound)
+}
+
+func TestRepository_UpdateUnchangedRowMySQL(t *testing.T) {
+	// given
+	repo := setupRepository(t)
+	ctx := context.Background()
+	alice := &repoTestUser{Name: "alice", Age: 30}
+	assert.NoError(t, repo.Insert(ctx, alice))
+	// MySQL reports rows with unchanged values as not affected, emulated by skipping every update
+	repo.db.dialect = DialectMySQL
+	_, err := repo.db.Exec("CREATE TRIGGER users_unchanged BEFORE UPDATE ON users BEGIN SELECT RAISE(IGNORE); END")
+	if err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+
+	// when
+	err = repo.Update(ctx, alice)
+
+	// then
+	assert.NoError(t, err)
+	assert.ErrorIs(t, repo.Update(ctx, &repoTestUser{ID: alice.ID + 1, Name: "bob"}), ErrRecordNotFound)
+}
+
+func TestNewRepository_InvalidMapping(t *testing.T) {
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+
+	type noPk struct {
+		Name string `db:"name"`
+	}
+	_, err = NewRepository[noPk](db, "users")
+	assert.Error(t, err)
+
+	type badOption struct {
+		ID int64 `db:"id,primary"`
+	}
+	_, err = NewRepository[badOption](db, "users")
+	assert.Error(t, err)
+}
+
+func setupRepository(t *testing.T) *Repository[repoTestUser] {
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, age INT NOT NULL)")
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	repo, err := NewRepository[repoTestUser](db, "users")
+	if err != nil {
+		t.Fatalf("NewRepository failed: %v", err)
+	}
+	return repo
+}
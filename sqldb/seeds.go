@@ -0,0 +1,65 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+)
+
+// RunSeeds loads seed files from seedsPath for the given environment (e.g. dev, test, prod).
+// See RunSeedsFS for the expected layout.
+func (db *SqlDb) RunSeeds(ctx context.Context, seedsPath string, env string) error {
+	log.Println("Running seeds from: ", seedsPath, env)
+	return db.RunSeedsFS(ctx, os.DirFS(seedsPath), ".", env)
+}
+
+// RunSeedsFS loads *.sql seed files located directly in root of fsys, followed by files in root/env.
+// Unlike migrations, seeds are not recorded and run on every call, so they must be idempotent
+// (INSERT OR IGNORE, upserts, ...). All files run in a single transaction where the dialect allows it.
+func (db *SqlDb) RunSeedsFS(ctx context.Context, fsys fs.FS, root string, env string) error {
+	if env == "" || path.Base(env) != env {
+		return fmt.Errorf("invalid seed environment: %q", env)
+	}
+
+	common, err := migrationFiles(fsys, root)
+	if err != nil {
+		return err
+	}
+	scoped, err := migrationFiles(fsys, path.Join(root, env))
+	if err != nil {
+		return err
+	}
+	files := append(common, scoped...)
+
+	var ex execer = db
+	var tx *sql.Tx
+	if db.dialect.transactionalDDL() {
+		tx, err = db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		ex = tx
+	}
+
+	for _, file := range files {
+		contents, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		log.Println("Seed applying: ", file)
+		err = db.dialect.execScript(ctx, ex, string(contents))
+		if err != nil {
+			return fmt.Errorf("seed %s failed: %w", file, err)
+		}
+	}
+
+	if tx != nil {
+		return tx.Commit()
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package sqldb
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSeedsFS(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	_, err = db.Exec("CREATE TABLE test_seeds (name TEXT PRIMARY KEY)")
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	fsys := fstest.MapFS{
+		"seeds/0_common.sql":    {Data: []byte("INSERT OR IGNORE INTO test_seeds (name) VALUES ('common');")},
+		"seeds/dev/0_users.sql": {Data: []byte("INSERT OR IGNORE INTO test_seeds (name) VALUES ('dev');")},
+		"seeds/prod/0_cfg.sql":  {Data: []byte("INSERT OR IGNORE INTO test_seeds (name) VALUES ('prod');")},
+	}
+
+	// when
+	assert.NoError(t, db.RunSeedsFS(ctx, fsys, "seeds", "dev"))
+	assert.NoError(t, db.RunSeedsFS(ctx, fsys, "seeds", "dev"))
+
+	// then
+	rows, err := db.Query("SELECT name FROM test_seeds ORDER BY name")
+	if err != nil {
+		t.Fatalf("Failed to query test_seeds: %v", err)
+	}
+	defer rows.Close()
+	names := []string{}
+	for rows.Next() {
+		var name string
+		rows.Scan(&name)
+		names = append(names, name)
+	}
+	assert.Equal(t, []string{"common", "dev"}, names)
+}
+
+func TestRunSeedsFS_FailureRollsBack(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	_, err = db.Exec("CREATE TABLE test_seeds (name TEXT PRIMARY KEY)")
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	fsys := fstest.MapFS{
+		"0_ok.sql":     {Data: []byte("INSERT INTO test_seeds (name) VALUES ('ok');")},
+		"1_broken.sql": {Data: []byte("INSERT INTO missing_table (name) VALUES ('broken');")},
+	}
+
+	// when
+	err = db.RunSeedsFS(ctx, fsys, ".", "test")
+
+	// then
+	assert.Error(t, err)
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM test_seeds").Scan(&count)
+	assert.Equal(t, 0, count)
+
+	assert.Error(t, db.RunSeedsFS(ctx, fsys, ".", "../prod"))
+}
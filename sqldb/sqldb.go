@@ -1,22 +1,122 @@
 package sqldb
 
 import (
+	"context"
 	"database/sql"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/go-sql-driver/mysql"
 )
 
 type SqlDb struct {
 	*sql.DB
+	dialect   Dialect
+	busyRetry busyRetry
+	// latestMigration holds md5 of the last migration file seen by a successful run of the
+	// application's migrations
+	latestMigration    atomic.Value
+	queryHook          QueryHook
+	slowQueryThreshold time.Duration
+	metrics            Metrics
+	goMigrationsMu     sync.Mutex
+	goMigrations       map[string]GoMigration
+	migrationOptions   MigrationOptions
+	reader             *SqlDb
+	// sqliteOptions are the options the database was opened with, reused by OpenReader
+	sqliteOptions SqliteOptions
 }
 
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// InitSqlite opens a sqlite database with DefaultSqliteOptions.
 func InitSqlite(dbPath string) (*SqlDb, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	return InitSqliteWithOptions(dbPath, DefaultSqliteOptions())
+}
+
+// InitSqliteWithOptions opens a sqlite database applying opts to every pooled connection.
+// Writes made through ExecCtx are retried on SQLITE_BUSY, see SetBusyRetry.
+func InitSqliteWithOptions(dbPath string, opts SqliteOptions) (*SqlDb, error) {
+	err := opts.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	dsn := dbPath
+	if opts.BusyTimeout > 0 {
+		dsn = withSqliteParam(dsn, "_busy_timeout", strconv.FormatInt(opts.BusyTimeout.Milliseconds(), 10))
+	}
+	db := sql.OpenDB(newSqliteConnector(dsn, opts))
+
+	return &SqlDb{
+		DB:      db,
+		dialect: DialectSqlite,
+		busyRetry: busyRetry{
+			attempts: DefaultBusyRetries,
+			delay:    DefaultBusyRetryDelay,
+		},
+		sqliteOptions: opts,
+	}, nil
+}
+
+// InitMySQL opens a MySQL/MariaDB database.
+// Multi-statement execution and time parsing are always enabled, since migration files rely on both.
+func InitMySQL(dsn string) (*SqlDb, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MultiStatements = true
+	cfg.ParseTime = true
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
 		return nil, err
 	}
 
 	return &SqlDb{
-		db,
+		DB:      db,
+		dialect: DialectMySQL,
 	}, nil
 }
+
+func (db *SqlDb) Dialect() Dialect {
+	return db.dialect
+}
+
+// ExecCtx executes a query written with $N placeholders, rewriting them for the database dialect.
+// Sqlite writes failing with SQLITE_BUSY are retried, see SetBusyRetry.
+func (db *SqlDb) ExecCtx(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	rebound, boundArgs := db.dialect.rebind(query, args)
+	var result sql.Result
+	err := db.retryOnBusy(ctx, func() error {
+		var err error
+		result, err = db.ExecContext(ctx, rebound, boundArgs...)
+		return err
+	})
+	db.observeQuery(ctx, query, args, start, err)
+	return result, err
+}
+
+// QueryCtx runs a query written with $N placeholders, rewriting them for the database dialect.
+func (db *SqlDb) QueryCtx(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rebound, boundArgs := db.dialect.rebind(query, args)
+	rows, err := db.QueryContext(ctx, rebound, boundArgs...)
+	db.observeQuery(ctx, query, args, start, err)
+	return rows, err
+}
+
+// QueryRowCtx runs a query written with $N placeholders, rewriting them for the database dialect.
+func (db *SqlDb) QueryRowCtx(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	rebound, boundArgs := db.dialect.rebind(query, args)
+	row := db.QueryRowContext(ctx, rebound, boundArgs...)
+	db.observeQuery(ctx, query, args, start, row.Err())
+	return row
+}
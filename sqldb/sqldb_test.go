@@ -1,6 +1,11 @@
 package sqldb
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestSqliteInMemory(t *testing.T) {
 	db, err := InitSqlite(":memory:")
@@ -9,3 +14,55 @@ func TestSqliteInMemory(t *testing.T) {
 	}
 	defer db.Close()
 }
+
+func TestCtxHelpers(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	_, err = db.ExecCtx(ctx, "CREATE TABLE test_ctx (a TEXT NOT NULL, b INT NOT NULL)")
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	// when
+	_, err = db.ExecCtx(ctx, "INSERT INTO test_ctx (a, b) VALUES ($1, $2)", "foo", 42)
+
+	// then
+	assert.NoError(t, err)
+
+	var b int
+	err = db.QueryRowCtx(ctx, "SELECT b FROM test_ctx WHERE a = $1", "foo").Scan(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, b)
+
+	rows, err := db.QueryCtx(ctx, "SELECT a FROM test_ctx WHERE b = $1", 42)
+	if err != nil {
+		t.Fatalf("QueryCtx failed: %v", err)
+	}
+	defer rows.Close()
+	assert.True(t, rows.Next())
+}
+
+func TestCtxHelpers_CanceledContext(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	_, err = db.ExecCtx(ctx, "CREATE TABLE test_ctx (a TEXT NOT NULL)")
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, db.RunMigrationsCtx(ctx, t.TempDir()), context.Canceled)
+}
@@ -0,0 +1,119 @@
+// Package sqldbtest provides helpers for tests of code built on sqldb.
+package sqldbtest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+	"gopkg.in/yaml.v3"
+)
+
+// New opens an in-memory sqlite database closed at the end of the test and runs *.sql migrations
+// from root of migrations when it's not nil.
+// The pool is limited to a single connection, since every connection to :memory: is a separate database.
+func New(t testing.TB, migrations fs.FS) *sqldb.SqlDb {
+	t.Helper()
+
+	db, err := sqldb.InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if migrations != nil {
+		err = db.RunMigrationsFS(migrations, ".")
+		if err != nil {
+			t.Fatalf("RunMigrationsFS failed: %v", err)
+		}
+	}
+
+	return db
+}
+
+// LoadFixtures loads fixture files from fixtures in the given order.
+// *.sql files are executed as is. *.yaml/*.yml files map table names to lists of rows, inserted
+// table by table in the order of the file:
+//
+//	users:
+//	  - id: 1
+//	    name: alice
+func LoadFixtures(t testing.TB, db *sqldb.SqlDb, fixtures fs.FS, files ...string) {
+	t.Helper()
+
+	for _, file := range files {
+		contents, err := fs.ReadFile(fixtures, file)
+		if err != nil {
+			t.Fatalf("Failed to read fixture %s: %v", file, err)
+		}
+
+		switch path.Ext(file) {
+		case ".sql":
+			_, err = db.ExecContext(context.Background(), string(contents))
+		case ".yaml", ".yml":
+			err = loadYAML(db, contents)
+		default:
+			err = fmt.Errorf("unsupported fixture format")
+		}
+		if err != nil {
+			t.Fatalf("Failed to load fixture %s: %v", file, err)
+		}
+	}
+}
+
+func loadYAML(db *sqldb.SqlDb, contents []byte) error {
+	// decoded as a node to keep the table order of the file, so parents can precede children
+	var doc yaml.Node
+	err := yaml.Unmarshal(contents, &doc)
+	if err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	tables := doc.Content[0]
+	if tables.Kind != yaml.MappingNode {
+		return fmt.Errorf("expected a mapping of table names to rows at line %d", tables.Line)
+	}
+
+	for i := 0; i+1 < len(tables.Content); i += 2 {
+		table := tables.Content[i].Value
+		var rows []map[string]any
+		err = tables.Content[i+1].Decode(&rows)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", table, err)
+		}
+		for _, row := range rows {
+			err = insertRow(db, table, row)
+			if err != nil {
+				return fmt.Errorf("table %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+func insertRow(db *sqldb.SqlDb, table string, row map[string]any) error {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	placeholders := make([]string, len(columns))
+	args := make([]any, len(columns))
+	for i, column := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = row[column]
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	_, err := db.ExecCtx(context.Background(), query, args...)
+	return err
+}
@@ -0,0 +1,79 @@
+package sqldbtest
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithFixtures(t *testing.T) {
+	// given
+	migrations := fstest.MapFS{
+		"1_users.sql": {Data: []byte("CREATE TABLE users (id INT PRIMARY KEY, name TEXT NOT NULL, admin BOOLEAN NOT NULL DEFAULT FALSE);")},
+	}
+	fixtures := fstest.MapFS{
+		"users.yaml": {Data: []byte(`
+users:
+  - id: 1
+    name: alice
+    admin: true
+  - id: 2
+    name: bob
+`)},
+		"more_users.sql": {Data: []byte("INSERT INTO users (id, name) VALUES (3, 'carol'); INSERT INTO users (id, name) VALUES (4, 'dave');")},
+	}
+
+	// when
+	db := New(t, migrations)
+	LoadFixtures(t, db, fixtures, "users.yaml", "more_users.sql")
+
+	// then
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	var name string
+	err = db.QueryRow("SELECT name FROM users WHERE admin").Scan(&name)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", name)
+}
+
+func TestLoadFixtures_YAMLKeepsTableOrder(t *testing.T) {
+	// given
+	migrations := fstest.MapFS{
+		"1_schema.sql": {Data: []byte(`CREATE TABLE users (id INT PRIMARY KEY, name TEXT NOT NULL);
+CREATE TABLE orders (id INT PRIMARY KEY, user_id INT NOT NULL REFERENCES users (id));`)},
+	}
+	fixtures := fstest.MapFS{
+		"orders.yaml": {Data: []byte(`
+users:
+  - id: 1
+    name: alice
+orders:
+  - id: 10
+    user_id: 1
+`)},
+	}
+
+	db := New(t, migrations)
+	_, err := db.Exec("PRAGMA foreign_keys = ON")
+	if err != nil {
+		t.Fatalf("Failed to enable foreign keys: %v", err)
+	}
+
+	// when
+	LoadFixtures(t, db, fixtures, "orders.yaml")
+
+	// then
+	var name string
+	err = db.QueryRow("SELECT u.name FROM orders o JOIN users u ON u.id = o.user_id").Scan(&name)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", name)
+}
+
+func TestNew_WithoutMigrations(t *testing.T) {
+	db := New(t, nil)
+	assert.NoError(t, db.Ping())
+}
@@ -0,0 +1,76 @@
+package sqldb
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SqliteDSN builds sqlite connection strings understood by InitSqlite:
+//
+//	dsn := NewSqliteDSN("bot.db").JournalMode(JournalModeWAL).BusyTimeout(5 * time.Second).String()
+//	// file:bot.db?_busy_timeout=5000&_journal_mode=WAL
+type SqliteDSN struct {
+	path   string
+	params url.Values
+}
+
+func NewSqliteDSN(path string) *SqliteDSN {
+	return &SqliteDSN{
+		path:   path,
+		params: url.Values{},
+	}
+}
+
+// Memory opens an in-memory database; combine with SharedCache to share it between pooled connections
+// by the name passed to NewSqliteDSN.
+func (d *SqliteDSN) Memory() *SqliteDSN {
+	d.params.Set("mode", "memory")
+	return d
+}
+
+func (d *SqliteDSN) ReadOnly() *SqliteDSN {
+	d.params.Set("mode", "ro")
+	return d
+}
+
+func (d *SqliteDSN) SharedCache() *SqliteDSN {
+	d.params.Set("cache", "shared")
+	return d
+}
+
+func (d *SqliteDSN) JournalMode(mode JournalMode) *SqliteDSN {
+	d.params.Set("_journal_mode", string(mode))
+	return d
+}
+
+func (d *SqliteDSN) Synchronous(mode SynchronousMode) *SqliteDSN {
+	d.params.Set("_synchronous", string(mode))
+	return d
+}
+
+func (d *SqliteDSN) BusyTimeout(timeout time.Duration) *SqliteDSN {
+	d.params.Set("_busy_timeout", strconv.FormatInt(timeout.Milliseconds(), 10))
+	return d
+}
+
+func (d *SqliteDSN) ForeignKeys(enabled bool) *SqliteDSN {
+	d.params.Set("_foreign_keys", strconv.FormatBool(enabled))
+	return d
+}
+
+// TxLock sets the locking behaviour of BEGIN: "deferred", "immediate" or "exclusive".
+func (d *SqliteDSN) TxLock(lock string) *SqliteDSN {
+	d.params.Set("_txlock", lock)
+	return d
+}
+
+func (d *SqliteDSN) String() string {
+	path := strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23").Replace(d.path)
+	dsn := "file:" + path
+	if len(d.params) > 0 {
+		dsn += "?" + d.params.Encode()
+	}
+	return dsn
+}
@@ -0,0 +1,48 @@
+package sqldb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqliteDSN(t *testing.T) {
+	assert.Equal(t, "file:bot.db", NewSqliteDSN("bot.db").String())
+	assert.Equal(t,
+		"file:bot.db?_busy_timeout=5000&_foreign_keys=true&_journal_mode=WAL&_synchronous=NORMAL&_txlock=immediate",
+		NewSqliteDSN("bot.db").
+			JournalMode(JournalModeWAL).
+			Synchronous(SynchronousNormal).
+			BusyTimeout(5*time.Second).
+			ForeignKeys(true).
+			TxLock("immediate").
+			String(),
+	)
+	assert.Equal(t, "file:shared?cache=shared&mode=memory", NewSqliteDSN("shared").Memory().SharedCache().String())
+	assert.Equal(t, "file:odd%3fname%23.db?mode=ro", NewSqliteDSN("odd?name#.db").ReadOnly().String())
+}
+
+func TestSqliteDSN_Open(t *testing.T) {
+	// given
+	dsn := NewSqliteDSN(filepath.Join(t.TempDir(), "dsn?.db")).JournalMode(JournalModeWAL).ForeignKeys(true).String()
+
+	// when
+	db, err := InitSqlite(dsn)
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+
+	// then
+	var journalMode string
+	err = db.QueryRow("PRAGMA journal_mode").Scan(&journalMode)
+	assert.NoError(t, err)
+	assert.Equal(t, "wal", journalMode)
+
+	var foreignKeys int
+	err = db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, foreignKeys)
+}
@@ -0,0 +1,111 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+type JournalMode string
+
+const (
+	JournalModeDelete   JournalMode = "DELETE"
+	JournalModeTruncate JournalMode = "TRUNCATE"
+	JournalModePersist  JournalMode = "PERSIST"
+	JournalModeMemory   JournalMode = "MEMORY"
+	JournalModeWAL      JournalMode = "WAL"
+	JournalModeOff      JournalMode = "OFF"
+)
+
+type SynchronousMode string
+
+const (
+	SynchronousOff    SynchronousMode = "OFF"
+	SynchronousNormal SynchronousMode = "NORMAL"
+	SynchronousFull   SynchronousMode = "FULL"
+	SynchronousExtra  SynchronousMode = "EXTRA"
+)
+
+// SqliteOptions are applied as PRAGMAs to every connection opened by the pool.
+// Zero values keep the sqlite defaults.
+type SqliteOptions struct {
+	JournalMode JournalMode
+	ForeignKeys bool
+	Synchronous SynchronousMode
+	// CacheSize is a number of pages when positive and a size in KiB when negative, as in PRAGMA cache_size.
+	CacheSize int
+	// BusyTimeout is passed as _busy_timeout unless the path already sets it.
+	BusyTimeout time.Duration
+}
+
+func DefaultSqliteOptions() SqliteOptions {
+	return SqliteOptions{
+		BusyTimeout: DefaultBusyTimeout,
+	}
+}
+
+func (o SqliteOptions) validate() error {
+	switch o.JournalMode {
+	case "", JournalModeDelete, JournalModeTruncate, JournalModePersist, JournalModeMemory, JournalModeWAL, JournalModeOff:
+	default:
+		return fmt.Errorf("unsupported journal mode: %s", o.JournalMode)
+	}
+	switch o.Synchronous {
+	case "", SynchronousOff, SynchronousNormal, SynchronousFull, SynchronousExtra:
+	default:
+		return fmt.Errorf("unsupported synchronous mode: %s", o.Synchronous)
+	}
+	return nil
+}
+
+func (o SqliteOptions) pragmas() []string {
+	pragmas := []string{}
+	if o.JournalMode != "" {
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA journal_mode = %s", o.JournalMode))
+	}
+	if o.ForeignKeys {
+		pragmas = append(pragmas, "PRAGMA foreign_keys = ON")
+	}
+	if o.Synchronous != "" {
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA synchronous = %s", o.Synchronous))
+	}
+	if o.CacheSize != 0 {
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA cache_size = %d", o.CacheSize))
+	}
+	return pragmas
+}
+
+// sqliteConnector opens sqlite connections with a hook applying the configured pragmas.
+type sqliteConnector struct {
+	dsn    string
+	driver *sqlite3.SQLiteDriver
+}
+
+func newSqliteConnector(dsn string, opts SqliteOptions) *sqliteConnector {
+	pragmas := opts.pragmas()
+	return &sqliteConnector{
+		dsn: dsn,
+		driver: &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				for _, pragma := range pragmas {
+					_, err := conn.Exec(pragma, nil)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func (c *sqliteConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *sqliteConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,62 @@
+package sqldb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitSqliteWithOptions(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "options.db")
+	opts := SqliteOptions{
+		JournalMode: JournalModeWAL,
+		ForeignKeys: true,
+		Synchronous: SynchronousNormal,
+		CacheSize:   -4000,
+		BusyTimeout: 2 * time.Second,
+	}
+
+	// when
+	db, err := InitSqliteWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("InitSqliteWithOptions failed: %v", err)
+	}
+	defer db.Close()
+
+	// then
+	var journalMode string
+	err = db.QueryRow("PRAGMA journal_mode").Scan(&journalMode)
+	assert.NoError(t, err)
+	assert.Equal(t, "wal", journalMode)
+
+	var foreignKeys int
+	err = db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, foreignKeys)
+
+	var synchronous int
+	err = db.QueryRow("PRAGMA synchronous").Scan(&synchronous)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, synchronous)
+
+	var cacheSize int
+	err = db.QueryRow("PRAGMA cache_size").Scan(&cacheSize)
+	assert.NoError(t, err)
+	assert.Equal(t, -4000, cacheSize)
+
+	var busyTimeout int
+	err = db.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout)
+	assert.NoError(t, err)
+	assert.Equal(t, 2000, busyTimeout)
+}
+
+func TestInitSqliteWithOptions_Invalid(t *testing.T) {
+	_, err := InitSqliteWithOptions(":memory:", SqliteOptions{JournalMode: "WAL; DROP TABLE migrations"})
+	assert.Error(t, err)
+
+	_, err = InitSqliteWithOptions(":memory:", SqliteOptions{Synchronous: "SOMETIMES"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package sqldb
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// execScript executes a script statement by statement, see splitStatements.
+func (d Dialect) execScript(ctx context.Context, ex execer, script string) error {
+	for _, statement := range d.splitStatements(script) {
+		_, err := ex.ExecContext(ctx, statement)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements splits a SQL script on semicolons that terminate statements.
+// Semicolons inside string literals, quoted identifiers, comments and BEGIN ... END bodies
+// of CREATE TRIGGER/PROCEDURE/FUNCTION/EVENT statements are kept. Statements consisting only of
+// whitespace and comments are dropped.
+func (d Dialect) splitStatements(script string) []string {
+	statements := []string{}
+	start := 0
+	depth := 0
+	inCreate := false
+	blockCreate := false
+	kindKnown := false
+	hasCode := false
+
+	flush := func(end int) {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		start = end + 1
+		depth = 0
+		inCreate = false
+		blockCreate = false
+		kindKnown = false
+		hasCode = false
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		if end, ok := d.skipNonCode(script, i); ok {
+			if c == '\'' || c == '"' || c == '`' {
+				hasCode = true
+			}
+			i = end
+			continue
+		}
+
+		switch {
+		case c == ';' && depth == 0:
+			flush(i)
+		case isWordChar(c) && (i == 0 || !isWordChar(script[i-1])):
+			end := i
+			for end < len(script) && isWordChar(script[end]) {
+				end++
+			}
+			word := strings.ToUpper(script[i:end])
+			if !hasCode {
+				inCreate = word == "CREATE"
+			} else if inCreate && !kindKnown {
+				blockCreate, kindKnown = createKind(word)
+			}
+			if blockCreate {
+				next, nextEnd := nextWord(script, end)
+				delta, consumesNext := blockDelta(word, next)
+				if depth+delta >= 0 {
+					depth += delta
+				}
+				if consumesNext {
+					end = nextEnd
+				}
+			}
+			hasCode = true
+			i = end - 1
+		case !unicode.IsSpace(rune(c)):
+			hasCode = true
+		}
+	}
+	flush(len(script))
+
+	return statements
+}
+
+// createKind tells whether a word following CREATE names an object with a BEGIN ... END body,
+// and whether the word decides that at all. Other words, like OR REPLACE, TEMP or DEFINER
+// clauses, are skipped until the object type.
+func createKind(word string) (block bool, known bool) {
+	switch word {
+	case "TRIGGER", "PROCEDURE", "FUNCTION", "EVENT":
+		return true, true
+	case "TABLE", "INDEX", "UNIQUE", "FULLTEXT", "SPATIAL", "VIEW", "VIRTUAL", "DATABASE", "SCHEMA",
+		"USER", "ROLE", "SEQUENCE", "SERVER", "TABLESPACE", "LOGFILE":
+		return false, true
+	}
+	return false, false
+}
+
+// blockDelta tells how a keyword changes BEGIN ... END nesting, and whether it's a compound
+// END IF/LOOP/WHILE/REPEAT/CASE consuming the next word. MySQL's IF, LOOP, WHILE and REPEAT
+// blocks are never counted as opened, CASE is.
+func blockDelta(word string, next string) (delta int, consumesNext bool) {
+	switch word {
+	case "BEGIN", "CASE":
+		return 1, false
+	case "END":
+		switch next {
+		case "IF", "LOOP", "WHILE", "REPEAT":
+			return 0, true
+		case "CASE":
+			return -1, true
+		}
+		return -1, false
+	}
+	return 0, false
+}
+
+func nextWord(script string, from int) (string, int) {
+	for from < len(script) && unicode.IsSpace(rune(script[from])) {
+		from++
+	}
+	end := from
+	for end < len(script) && isWordChar(script[end]) {
+		end++
+	}
+	return strings.ToUpper(script[from:end]), end
+}
+
+// skipNonCode returns the index of the last character of a string literal, quoted identifier
+// or comment starting at i, and false if there is none. MySQL string literals may contain
+// backslash escapes.
+func (d Dialect) skipNonCode(script string, i int) (int, bool) {
+	c := script[i]
+	switch {
+	case c == '\'' || c == '"' || c == '`':
+		return skipQuoted(script, i, c, d == DialectMySQL && c != '`'), true
+	case c == '-' && i+1 < len(script) && script[i+1] == '-':
+		end := strings.IndexByte(script[i:], '\n')
+		if end < 0 {
+			return len(script) - 1, true
+		}
+		return i + end - 1, true
+	case c == '/' && i+1 < len(script) && script[i+1] == '*':
+		end := strings.Index(script[i+2:], "*/")
+		if end < 0 {
+			return len(script) - 1, true
+		}
+		return i + end + 3, true
+	}
+	return i, false
+}
+
+// skipQuoted returns the index of the quote closing the literal opened at start.
+// A doubled quote inside the literal is an escaped quote, as is a backslash-escaped one if
+// backslashEscapes is set.
+func skipQuoted(script string, start int, quote byte, backslashEscapes bool) int {
+	for i := start + 1; i < len(script); i++ {
+		if backslashEscapes && script[i] == '\\' {
+			i++
+			continue
+		}
+		if script[i] != quote {
+			continue
+		}
+		if i+1 < len(script) && script[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(script) - 1
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
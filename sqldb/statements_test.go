@@ -0,0 +1,141 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `
+-- leading comment; with semicolon
+CREATE TABLE a (x TEXT DEFAULT 'semi;colon', "odd;name" INT);
+/* block; comment */
+INSERT INTO a (x) VALUES ('it''s; fine');
+
+CREATE TRIGGER a_ai AFTER INSERT ON a
+BEGIN
+    UPDATE a SET x = CASE WHEN x = 'a' THEN 'b' ELSE x END WHERE rowid = NEW.rowid;
+    INSERT INTO log (msg) VALUES ('inserted');
+END;
+BEGIN;
+COMMIT;
+;
+-- trailing comment`
+
+	statements := DialectSqlite.splitStatements(script)
+
+	assert.Equal(t, []string{
+		`-- leading comment; with semicolon
+CREATE TABLE a (x TEXT DEFAULT 'semi;colon', "odd;name" INT)`,
+		`/* block; comment */
+INSERT INTO a (x) VALUES ('it''s; fine')`,
+		`CREATE TRIGGER a_ai AFTER INSERT ON a
+BEGIN
+    UPDATE a SET x = CASE WHEN x = 'a' THEN 'b' ELSE x END WHERE rowid = NEW.rowid;
+    INSERT INTO log (msg) VALUES ('inserted');
+END`,
+		`BEGIN`,
+		`COMMIT`,
+	}, statements)
+}
+
+func TestSplitStatements_MySQLProcedure(t *testing.T) {
+	script := `
+CREATE PROCEDURE p()
+BEGIN
+    IF 1 = 1 THEN
+        SELECT 1;
+    END IF;
+    WHILE 0 DO
+        SELECT 2;
+    END WHILE;
+END;
+SELECT 3`
+
+	statements := DialectSqlite.splitStatements(script)
+
+	assert.Len(t, statements, 2)
+	assert.Equal(t, "SELECT 3", statements[1])
+}
+
+func TestRunMigrations_Trigger(t *testing.T) {
+	// given
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	script := `
+	CREATE TABLE test_trigger (a TEXT NOT NULL);
+	CREATE TABLE test_trigger_log (msg TEXT NOT NULL);
+	CREATE TRIGGER test_trigger_ai AFTER INSERT ON test_trigger
+	BEGIN
+		INSERT INTO test_trigger_log (msg) VALUES ('inserted; ' || NEW.a);
+	END;
+	INSERT INTO test_trigger (a) VALUES ('foo');
+	`
+	path := setupMigrationFiles([]string{script})
+	defer removeTempDir(path)
+
+	// when
+	err = db.RunMigrations(path)
+
+	// then
+	assert.NoError(t, err)
+	var msg string
+	err = db.QueryRow("SELECT msg FROM test_trigger_log").Scan(&msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "inserted; foo", msg)
+}
+
+func TestSplitStatements_EndAndBeginColumns(t *testing.T) {
+	script := `
+CREATE TABLE events (id INT, begin BIGINT, "end" BIGINT, end BIGINT);
+CREATE INDEX events_end ON events (end);
+INSERT INTO events (id, end) VALUES (1, 2)`
+
+	statements := DialectSqlite.splitStatements(script)
+
+	assert.Equal(t, []string{
+		`CREATE TABLE events (id INT, begin BIGINT, "end" BIGINT, end BIGINT)`,
+		`CREATE INDEX events_end ON events (end)`,
+		`INSERT INTO events (id, end) VALUES (1, 2)`,
+	}, statements)
+}
+
+func TestSplitStatements_MySQLEndCase(t *testing.T) {
+	script := `
+CREATE OR REPLACE PROCEDURE p(x INT)
+BEGIN
+    CASE x
+        WHEN 1 THEN SELECT 1;
+        ELSE SELECT 2;
+    END CASE;
+END;
+SELECT 3`
+
+	statements := DialectMySQL.splitStatements(script)
+
+	assert.Len(t, statements, 2)
+	assert.Equal(t, "SELECT 3", statements[1])
+}
+
+func TestSplitStatements_BackslashEscapes(t *testing.T) {
+	script := `INSERT INTO a (x) VALUES ('it\'s; fine');
+INSERT INTO a (x) VALUES ('C:\\');
+SELECT 1`
+
+	mysql := DialectMySQL.splitStatements(script)
+	sqlite := DialectSqlite.splitStatements(`INSERT INTO a (x) VALUES ('C:\');
+SELECT 1`)
+
+	assert.Equal(t, []string{
+		`INSERT INTO a (x) VALUES ('it\'s; fine')`,
+		`INSERT INTO a (x) VALUES ('C:\\')`,
+		`SELECT 1`,
+	}, mysql)
+	assert.Equal(t, []string{`INSERT INTO a (x) VALUES ('C:\')`, `SELECT 1`}, sqlite)
+}
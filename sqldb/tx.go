@@ -0,0 +1,121 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Tx is a transaction started by WithTx. Its Ctx helpers rewrite $N placeholders, retry busy writes
+// and report to query hooks and metrics like the ones of SqlDb.
+type Tx struct {
+	*sql.Tx
+	db    *SqlDb
+	depth int
+}
+
+type txKey struct {
+	db *SqlDb
+}
+
+// WithTx runs fn in a transaction, committing when fn returns nil and rolling back on error or panic.
+// The transaction is carried in the context passed to fn: a nested WithTx call with that context
+// uses a SAVEPOINT of the outer transaction, so a failing inner block is rolled back on its own
+// while the outer transaction decides about the final commit.
+func (db *SqlDb) WithTx(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) (err error) {
+	if outer, ok := ctx.Value(txKey{db}).(*Tx); ok {
+		return outer.withSavepoint(ctx, fn)
+	}
+
+	sqlTx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	tx := &Tx{Tx: sqlTx, db: db}
+
+	defer func() {
+		if p := recover(); p != nil {
+			sqlTx.Rollback()
+			panic(p)
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txKey{db}, tx), tx)
+	if err != nil {
+		sqlTx.Rollback()
+		return err
+	}
+	return sqlTx.Commit()
+}
+
+func (tx *Tx) withSavepoint(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) (err error) {
+	nested := &Tx{Tx: tx.Tx, db: tx.db, depth: tx.depth + 1}
+	savepoint := fmt.Sprintf("sp_%d", nested.depth)
+
+	_, err = tx.ExecContext(ctx, "SAVEPOINT "+savepoint)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.rollbackToSavepoint(ctx, savepoint)
+			panic(p)
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txKey{tx.db}, nested), nested)
+	if err != nil {
+		if rollbackErr := tx.rollbackToSavepoint(ctx, savepoint); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+	return err
+}
+
+// rollbackToSavepoint undoes the changes made since savepoint and releases it, since ROLLBACK TO
+// keeps the savepoint on the stack.
+func (tx *Tx) rollbackToSavepoint(ctx context.Context, savepoint string) error {
+	_, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+	if rollbackErr != nil {
+		rollbackErr = fmt.Errorf("failed to roll back to savepoint %s: %w", savepoint, rollbackErr)
+	}
+	_, releaseErr := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+	if releaseErr != nil {
+		releaseErr = fmt.Errorf("failed to release savepoint %s: %w", savepoint, releaseErr)
+	}
+	return errors.Join(rollbackErr, releaseErr)
+}
+
+func (tx *Tx) ExecCtx(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	rebound, boundArgs := tx.db.dialect.rebind(query, args)
+	var result sql.Result
+	err := tx.db.retryOnBusy(ctx, func() error {
+		var err error
+		result, err = tx.ExecContext(ctx, rebound, boundArgs...)
+		return err
+	})
+	tx.db.observeQuery(ctx, query, args, start, err)
+	return result, err
+}
+
+func (tx *Tx) QueryCtx(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rebound, boundArgs := tx.db.dialect.rebind(query, args)
+	rows, err := tx.QueryContext(ctx, rebound, boundArgs...)
+	tx.db.observeQuery(ctx, query, args, start, err)
+	return rows, err
+}
+
+func (tx *Tx) QueryRowCtx(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	rebound, boundArgs := tx.db.dialect.rebind(query, args)
+	row := tx.QueryRowContext(ctx, rebound, boundArgs...)
+	tx.db.observeQuery(ctx, query, args, start, row.Err())
+	return row
+}
@@ -0,0 +1,127 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTx_Nested(t *testing.T) {
+	// given
+	db := setupTxDb(t)
+	ctx := context.Background()
+
+	// when
+	err := db.WithTx(ctx, func(ctx context.Context, tx *Tx) error {
+		_, err := tx.ExecCtx(ctx, "INSERT INTO test_tx (a) VALUES ($1)", "outer")
+		if err != nil {
+			return err
+		}
+
+		innerErr := db.WithTx(ctx, func(ctx context.Context, tx *Tx) error {
+			tx.ExecCtx(ctx, "INSERT INTO test_tx (a) VALUES ($1)", "failed inner")
+			return errors.New("inner failure")
+		})
+		assert.EqualError(t, innerErr, "inner failure")
+
+		return db.WithTx(ctx, func(ctx context.Context, tx *Tx) error {
+			_, err := tx.ExecCtx(ctx, "INSERT INTO test_tx (a) VALUES ($1)", "inner")
+			return err
+		})
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"outer", "inner"}, txValues(t, db))
+}
+
+func TestWithTx_FailedSavepointIsReleased(t *testing.T) {
+	// given
+	db := setupTxDb(t)
+	ctx := context.Background()
+	innerFailure := errors.New("inner failure")
+
+	// when
+	err := db.WithTx(ctx, func(ctx context.Context, tx *Tx) error {
+		innerErr := db.WithTx(ctx, func(ctx context.Context, tx *Tx) error {
+			return innerFailure
+		})
+		assert.Same(t, innerFailure, innerErr)
+
+		_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT sp_1")
+		return err
+	})
+
+	// then
+	assert.ErrorContains(t, err, "no such savepoint")
+}
+
+func TestWithTx_OuterFailureRollsBackInner(t *testing.T) {
+	// given
+	db := setupTxDb(t)
+	ctx := context.Background()
+
+	// when
+	err := db.WithTx(ctx, func(ctx context.Context, tx *Tx) error {
+		err := db.WithTx(ctx, func(ctx context.Context, tx *Tx) error {
+			_, err := tx.ExecCtx(ctx, "INSERT INTO test_tx (a) VALUES ($1)", "inner")
+			return err
+		})
+		assert.NoError(t, err)
+		return errors.New("outer failure")
+	})
+
+	// then
+	assert.EqualError(t, err, "outer failure")
+	assert.Empty(t, txValues(t, db))
+}
+
+func TestWithTx_Panic(t *testing.T) {
+	// given
+	db := setupTxDb(t)
+	ctx := context.Background()
+
+	// when
+	assert.Panics(t, func() {
+		db.WithTx(ctx, func(ctx context.Context, tx *Tx) error {
+			tx.ExecCtx(ctx, "INSERT INTO test_tx (a) VALUES ($1)", "panicked")
+			panic("boom")
+		})
+	})
+
+	// then
+	assert.Empty(t, txValues(t, db))
+}
+
+func setupTxDb(t *testing.T) *SqlDb {
+	db, err := InitSqlite(":memory:")
+	if err != nil {
+		t.Fatalf("InitSqlite failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE test_tx (a TEXT NOT NULL)")
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	return db
+}
+
+func txValues(t *testing.T, db *SqlDb) []string {
+	rows, err := db.Query("SELECT a FROM test_tx ORDER BY rowid")
+	if err != nil {
+		t.Fatalf("Failed to query test_tx: %v", err)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var a string
+		rows.Scan(&a)
+		values = append(values, a)
+	}
+	return values
+}
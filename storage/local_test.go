@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStore(t *testing.T) {
+	// given
+	ctx := context.Background()
+	s, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	// when
+	assert.NoError(t, s.Put(ctx, "backups/b.db", strings.NewReader("b")))
+	assert.NoError(t, s.Put(ctx, "backups/a.db", strings.NewReader("a")))
+	assert.NoError(t, s.Put(ctx, "files/photo.jpg", strings.NewReader("jpg")))
+
+	// then
+	r, err := s.Get(ctx, "backups/a.db")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "a", string(data))
+
+	exists, err := s.Exists(ctx, "files/photo.jpg")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	keys, err := s.List(ctx, "backups/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backups/a.db", "backups/b.db"}, keys)
+
+	assert.NoError(t, s.Delete(ctx, "backups/a.db"))
+	assert.NoError(t, s.Delete(ctx, "backups/a.db"))
+	_, err = s.Get(ctx, "backups/a.db")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, err, errs.NotFound)
+	exists, err = s.Exists(ctx, "backups/a.db")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestLocalStoreListSkipsHiddenFiles(t *testing.T) {
+	// given
+	root := t.TempDir()
+	s, err := NewLocalStore(root)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Put(context.Background(), "a.txt", strings.NewReader("a")))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".a.txt.tmp123"), []byte("partial"), 0o644))
+
+	// when
+	keys, err := s.List(context.Background(), "")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt"}, keys)
+}
+
+func TestLocalStoreRejectsInvalidKeys(t *testing.T) {
+	// given
+	s, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	for _, key := range []string{"", "/abs", "dir/", "../escape", "a//b", "a/./b"} {
+		// when
+		err := s.Put(context.Background(), key, strings.NewReader("x"))
+
+		// then
+		assert.Error(t, err, key)
+	}
+}
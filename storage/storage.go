@@ -0,0 +1,37 @@
+// Package storage is a simple blob store abstraction with local disk and S3-compatible backends.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+)
+
+var ErrNotFound = errs.New(errs.NotFound, "blob not found")
+
+// Store keeps blobs under slash-separated keys like "backups/2024-03-10.db".
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get returns ErrNotFound for missing keys. The caller closes the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete doesn't fail for missing keys.
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	// List returns sorted keys starting with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") {
+		return fmt.Errorf("invalid blob key: %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid blob key: %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package system
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Set at link time, see Ldflags.
+var (
+	version = "dev"
+	commit  = ""
+)
+
+var startTime = time.Now()
+
+type BuildInfo struct {
+	Version   string    `json:"version"`
+	Commit    string    `json:"commit,omitempty"`
+	StartTime time.Time `json:"start_time"`
+	Uptime    string    `json:"uptime"`
+}
+
+// GetBuildInfo returns the version and commit injected at link time. If commit wasn't injected,
+// the VCS revision recorded by the go toolchain is used.
+func GetBuildInfo() BuildInfo {
+	c := commit
+	if c == "" {
+		c = vcsRevision()
+	}
+	return BuildInfo{
+		Version:   version,
+		Commit:    c,
+		StartTime: startTime,
+		Uptime:    Uptime().Truncate(time.Second).String(),
+	}
+}
+
+func Version() string {
+	return version
+}
+
+func StartTime() time.Time {
+	return startTime
+}
+
+// Uptime returns time since the process started (more precisely, since the package was initialized).
+func Uptime() time.Duration {
+	return time.Since(startTime)
+}
+
+// Ldflags returns linker flags injecting version and commit, for build scripts:
+//
+//	go build -ldflags "-X github.com/denis-kilchichakov/toolbox/system.version=v1.2.3 ..." ./cmd/bot
+func Ldflags(version, commit string) string {
+	const pkg = "github.com/denis-kilchichakov/toolbox/system"
+	return fmt.Sprintf("-X %s.version=%s -X %s.commit=%s", pkg, version, pkg, commit)
+}
+
+func vcsRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+	return ""
+}
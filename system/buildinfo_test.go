@@ -0,0 +1,30 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBuildInfo(t *testing.T) {
+	// given
+	defer func(v, c string) { version, commit = v, c }(version, commit)
+	version, commit = "1.2.3", "abc123"
+
+	// when
+	info := GetBuildInfo()
+
+	// then
+	assert.Equal(t, "1.2.3", info.Version)
+	assert.Equal(t, "abc123", info.Commit)
+	assert.Equal(t, startTime, info.StartTime)
+	assert.Equal(t, "1.2.3", Version())
+	assert.WithinDuration(t, time.Now(), StartTime().Add(Uptime()), time.Second)
+}
+
+func TestLdflags(t *testing.T) {
+	assert.Equal(t,
+		"-X github.com/denis-kilchichakov/toolbox/system.version=v1 -X github.com/denis-kilchichakov/toolbox/system.commit=abc",
+		Ldflags("v1", "abc"))
+}
@@ -0,0 +1,101 @@
+package system
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	crashDumpMu  sync.Mutex
+	crashDumpDir string
+)
+
+// EnableCrashDumps makes Main write a crash dump to dir on a panic, and installs a SIGABRT handler
+// that writes one and exits. A nil-pointer dereference or another fault in Go code is a panic too;
+// faults in cgo code can't be intercepted and are only reported by the runtime on stderr.
+// stop uninstalls the handler and disables dumps.
+func EnableCrashDumps(dir string) (stop func(), err error) {
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create crash dump directory: %w", err)
+	}
+	setCrashDumpDir(dir)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGABRT)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-sigs:
+			writeCrashDumpIfEnabled(fmt.Sprintf("signal: %s", sig))
+			exit(exitPanic)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+			setCrashDumpDir("")
+		})
+	}, nil
+}
+
+// WriteCrashDump writes reason, build info, memory stats and stacks of all goroutines to a new
+// file in dir and returns its path.
+func WriteCrashDump(dir string, reason string) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%d.txt", time.Now().UTC().Format("20060102T150405"), os.Getpid()))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create crash dump: %w", err)
+	}
+	defer f.Close()
+
+	build := GetBuildInfo()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	fmt.Fprintf(f, "reason: %s\n", reason)
+	fmt.Fprintf(f, "time: %s\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(f, "version: %s\ncommit: %s\nuptime: %s\n\n", build.Version, build.Commit, build.Uptime)
+	fmt.Fprintf(f, "goroutines: %d\nheap_alloc: %d\nheap_sys: %d\nheap_objects: %d\nnum_gc: %d\n\n",
+		runtime.NumGoroutine(), mem.HeapAlloc, mem.HeapSys, mem.HeapObjects, mem.NumGC)
+
+	err = pprof.Lookup("goroutine").WriteTo(f, 2)
+	if err != nil {
+		return path, fmt.Errorf("failed to write goroutine dump: %w", err)
+	}
+	return path, f.Close()
+}
+
+func setCrashDumpDir(dir string) {
+	crashDumpMu.Lock()
+	defer crashDumpMu.Unlock()
+	crashDumpDir = dir
+}
+
+func writeCrashDumpIfEnabled(reason string) {
+	crashDumpMu.Lock()
+	dir := crashDumpDir
+	crashDumpMu.Unlock()
+	if dir == "" {
+		return
+	}
+
+	path, err := WriteCrashDump(dir, reason)
+	if err != nil {
+		log.Println("Failed to write crash dump: ", err)
+		return
+	}
+	log.Println("Crash dump written to ", path)
+}
@@ -0,0 +1,93 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCrashDump(t *testing.T) {
+	// given
+	dir := t.TempDir()
+
+	// when
+	path, err := WriteCrashDump(dir, "test")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(path))
+	dump, _ := os.ReadFile(path)
+	assert.Contains(t, string(dump), "reason: test\n")
+	assert.Contains(t, string(dump), "heap_alloc: ")
+	assert.Contains(t, string(dump), "TestWriteCrashDump")
+}
+
+func TestEnableCrashDumps_Panic(t *testing.T) {
+	// given
+	captureLog(t)
+	dir := filepath.Join(t.TempDir(), "crashes")
+	stop, err := EnableCrashDumps(dir)
+	if err != nil {
+		t.Fatalf("EnableCrashDumps failed: %v", err)
+	}
+	defer stop()
+
+	// when
+	code := runMain(func(ctx context.Context) error {
+		panic("kaboom")
+	})
+
+	// then
+	assert.Equal(t, exitPanic, code)
+	dumps, _ := filepath.Glob(filepath.Join(dir, "crash-*.txt"))
+	assert.Len(t, dumps, 1)
+	dump, _ := os.ReadFile(dumps[0])
+	assert.Contains(t, string(dump), "reason: panic: kaboom\n")
+}
+
+func TestEnableCrashDumps_Signal(t *testing.T) {
+	// given
+	captureLog(t)
+	dir := t.TempDir()
+	exited := make(chan int, 1)
+	exit = func(code int) { exited <- code }
+	defer func() { exit = os.Exit }()
+	stop, err := EnableCrashDumps(dir)
+	if err != nil {
+		t.Fatalf("EnableCrashDumps failed: %v", err)
+	}
+	defer stop()
+
+	// when
+	sendSignal(t, syscall.SIGABRT)
+
+	// then
+	select {
+	case code := <-exited:
+		assert.Equal(t, exitPanic, code)
+	case <-time.After(time.Second):
+		t.Fatal("process did not exit on SIGABRT")
+	}
+	dumps, _ := filepath.Glob(filepath.Join(dir, "crash-*.txt"))
+	assert.Len(t, dumps, 1)
+}
+
+func TestEnableCrashDumps_Stop(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	stop, _ := EnableCrashDumps(dir)
+
+	// when
+	stop()
+	stop()
+	writeCrashDumpIfEnabled("test")
+
+	// then
+	dumps, _ := filepath.Glob(filepath.Join(dir, "*"))
+	assert.Empty(t, dumps)
+}
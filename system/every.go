@@ -0,0 +1,71 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime/debug"
+	"time"
+)
+
+type EveryOptions struct {
+	Interval time.Duration
+	// Jitter adds a random delay in [0, Jitter) to every interval, so instances started together
+	// don't hit shared dependencies at the same moment.
+	Jitter time.Duration
+	// Immediate runs fn once right away instead of after the first interval.
+	Immediate bool
+}
+
+// Every calls fn every interval until ctx is done, see EveryWithOptions.
+func Every(ctx context.Context, interval time.Duration, fn func(ctx context.Context) error) error {
+	return EveryWithOptions(ctx, EveryOptions{Interval: interval}, fn)
+}
+
+// EveryWithOptions calls fn periodically until ctx is done and returns ctx.Err(). Runs never
+// overlap: the interval is measured from the end of a run to the start of the next one. Errors
+// and panics of fn are logged and don't stop the loop.
+func EveryWithOptions(ctx context.Context, opts EveryOptions, fn func(ctx context.Context) error) error {
+	if opts.Interval <= 0 {
+		return fmt.Errorf("periodic task interval must be positive: %s", opts.Interval)
+	}
+	if opts.Immediate {
+		runPeriodic(ctx, fn)
+	}
+
+	timer := time.NewTimer(opts.next())
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			runPeriodic(ctx, fn)
+			timer.Reset(opts.next())
+		}
+	}
+}
+
+func (o EveryOptions) next() time.Duration {
+	if o.Jitter <= 0 {
+		return o.Interval
+	}
+	return o.Interval + time.Duration(rand.Int63n(int64(o.Jitter)))
+}
+
+func runPeriodic(ctx context.Context, fn func(ctx context.Context) error) {
+	if ctx.Err() != nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Periodic task panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	err := fn(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Println("Periodic task failed: ", err)
+	}
+}
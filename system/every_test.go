@@ -0,0 +1,75 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	// given
+	var calls atomic.Int32
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+
+	// when
+	err := Every(ctx, 10*time.Millisecond, func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, calls.Load(), int32(3))
+	assert.Error(t, Every(ctx, 0, nil))
+}
+
+func TestEveryWithOptions_SurvivesErrorsAndPanics(t *testing.T) {
+	// given
+	captureLog(t)
+	var calls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// when
+	err := EveryWithOptions(ctx, EveryOptions{Interval: time.Millisecond, Jitter: time.Millisecond, Immediate: true},
+		func(ctx context.Context) error {
+			switch calls.Add(1) {
+			case 1:
+				return errors.New("boom")
+			case 2:
+				panic("kaboom")
+			default:
+				cancel()
+				return nil
+			}
+		})
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestEveryWithOptions_NoOverlap(t *testing.T) {
+	// given
+	var running, overlaps atomic.Int32
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// when
+	EveryWithOptions(ctx, EveryOptions{Interval: time.Millisecond}, func(ctx context.Context) error {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		defer running.Add(-1)
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+
+	// then
+	assert.Zero(t, overlaps.Load())
+}
@@ -0,0 +1,142 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/httpserver"
+)
+
+// DefaultHealthCheckTimeout bounds a single /readyz evaluation.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// HealthCheck reports whether a dependency is ready, e.g. sqldb's (*SqlDb).HealthCheck.
+type HealthCheck func(ctx context.Context) error
+
+type HealthReport struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+	Build  *BuildInfo        `json:"build,omitempty"`
+}
+
+// HealthServer exposes /healthz (the process is alive, with its BuildInfo) and /readyz (all
+// registered checks pass).
+type HealthServer struct {
+	CheckTimeout time.Duration
+
+	mu     sync.Mutex
+	checks map[string]HealthCheck
+	server *httpserver.Server
+}
+
+func NewHealthServer(addr string) *HealthServer {
+	h := &HealthServer{
+		CheckTimeout: DefaultHealthCheckTimeout,
+		checks:       map[string]HealthCheck{},
+	}
+	opts := httpserver.DefaultOptions(addr)
+	opts.ShutdownTimeout = DefaultHealthCheckTimeout
+	h.server = httpserver.NewWithMiddleware(h.Handler(), opts, httpserver.Recovery)
+	return h
+}
+
+// AddCheck registers a readiness check under name, replacing an existing one with the same name.
+func (h *HealthServer) AddCheck(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+func (h *HealthServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		build := GetBuildInfo()
+		writeHealthReport(w, http.StatusOK, HealthReport{Status: "ok", Build: &build})
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		report := h.Check(r.Context())
+		status := http.StatusOK
+		if report.Status != "ok" {
+			status = http.StatusServiceUnavailable
+		}
+		writeHealthReport(w, status, report)
+	})
+	return mux
+}
+
+// Check runs all readiness checks concurrently within CheckTimeout.
+func (h *HealthServer) Check(ctx context.Context) HealthReport {
+	h.mu.Lock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]HealthCheck, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.Unlock()
+
+	if h.CheckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.CheckTimeout)
+		defer cancel()
+	}
+
+	results := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			results[i] = check(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := HealthReport{
+		Status: "ok",
+		Checks: map[string]string{},
+	}
+	for i, name := range names {
+		if results[i] != nil {
+			report.Status = "fail"
+			report.Checks[name] = results[i].Error()
+		} else {
+			report.Checks[name] = "ok"
+		}
+	}
+	return report
+}
+
+// Start binds the address and serves in background.
+func (h *HealthServer) Start() error {
+	return h.server.Start()
+}
+
+// Addr returns the bound address after Start, useful when listening on port 0.
+func (h *HealthServer) Addr() string {
+	return h.server.Addr()
+}
+
+// Stop gracefully shuts the server down. Its signature matches CloseFunc, so it can be registered
+// with a Shutdowner.
+func (h *HealthServer) Stop(ctx context.Context) error {
+	return h.server.Stop(ctx)
+}
+
+// Run starts the server and stops it once ctx is done, so it can be passed to system.Run.
+func (h *HealthServer) Run(ctx context.Context) error {
+	return h.server.Run(ctx)
+}
+
+func writeHealthReport(w http.ResponseWriter, status int, report HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,88 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthServer(t *testing.T) {
+	// given
+	h := NewHealthServer("127.0.0.1:0")
+	dbErr := errors.New("database is locked")
+	var dbState error
+	h.AddCheck("db", func(ctx context.Context) error { return dbState })
+	h.AddCheck("llm", func(ctx context.Context) error { return nil })
+
+	// when
+	err := h.Start()
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer h.Stop(context.Background())
+
+	// then
+	status, report := getHealth(t, "http://"+h.Addr()+"/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", report.Status)
+	assert.Equal(t, Version(), report.Build.Version)
+
+	status, report = getHealth(t, "http://"+h.Addr()+"/readyz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, HealthReport{Status: "ok", Checks: map[string]string{"db": "ok", "llm": "ok"}}, report)
+
+	dbState = dbErr
+	status, report = getHealth(t, "http://"+h.Addr()+"/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, HealthReport{Status: "fail", Checks: map[string]string{"db": "database is locked", "llm": "ok"}}, report)
+}
+
+func TestHealthServer_CheckTimeout(t *testing.T) {
+	// given
+	h := NewHealthServer("127.0.0.1:0")
+	h.CheckTimeout = 20 * time.Millisecond
+	h.AddCheck("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	// when
+	report := h.Check(context.Background())
+
+	// then
+	assert.Equal(t, "fail", report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"])
+}
+
+func TestHealthServer_Stop(t *testing.T) {
+	h := NewHealthServer("127.0.0.1:0")
+	assert.NoError(t, h.Stop(context.Background()), "stop before start is a no-op")
+
+	assert.NoError(t, h.Start())
+	assert.Error(t, h.Start())
+	addr := h.Addr()
+	assert.NoError(t, h.Stop(context.Background()))
+
+	_, err := http.Get("http://" + addr + "/healthz")
+	assert.Error(t, err)
+}
+
+func getHealth(t *testing.T, url string) (int, HealthReport) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	var report HealthReport
+	err = json.NewDecoder(resp.Body).Decode(&report)
+	if err != nil {
+		t.Fatalf("Failed to decode health report: %v", err)
+	}
+	return resp.StatusCode, report
+}
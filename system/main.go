@@ -0,0 +1,43 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+)
+
+const (
+	exitError = 1
+	exitPanic = 2
+)
+
+var exit = os.Exit
+
+// Main is the entry point for cmd/* binaries. It calls run with a ShutdownContext, logs a
+// returned error or a panic with its stack trace, and exits with a non-zero code on failure:
+// 1 for an error and 2 for a panic. Panics in goroutines started by run are not recovered.
+// See EnableCrashDumps for post-mortem dumps.
+func Main(run func(ctx context.Context) error) {
+	exit(runMain(run))
+}
+
+func runMain(run func(ctx context.Context) error) (code int) {
+	ctx, stop := ShutdownContext()
+	defer stop()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("panic: %v\n%s", r, debug.Stack())
+			writeCrashDumpIfEnabled(fmt.Sprintf("panic: %v", r))
+			code = exitPanic
+		}
+	}()
+
+	if err := run(ctx); err != nil {
+		log.Printf("error: %v", err)
+		return exitError
+	}
+	return 0
+}
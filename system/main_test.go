@@ -0,0 +1,64 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestMain_Success(t *testing.T) {
+	// given
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	// when
+	Main(func(ctx context.Context) error {
+		assert.NotNil(t, ctx.Done())
+		return nil
+	})
+
+	// then
+	assert.Equal(t, 0, code)
+}
+
+func TestRunMain_Error(t *testing.T) {
+	// given
+	out := captureLog(t)
+
+	// when
+	code := runMain(func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+
+	// then
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, out.String(), "error: boom")
+}
+
+func TestRunMain_Panic(t *testing.T) {
+	// given
+	out := captureLog(t)
+
+	// when
+	code := runMain(func(ctx context.Context) error {
+		panic("kaboom")
+	})
+
+	// then
+	assert.Equal(t, exitPanic, code)
+	assert.Contains(t, out.String(), "panic: kaboom")
+	assert.Contains(t, out.String(), "main_test.go")
+}
@@ -0,0 +1,127 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+)
+
+type ResourceStats struct {
+	Goroutines int
+	HeapAlloc  uint64
+	HeapSys    uint64
+	// OpenFDs is -1 where it can't be determined (only Linux is supported).
+	OpenFDs   int
+	SampledAt time.Time
+}
+
+// ResourceThresholds are upper limits for resource usage. Zero values disable a limit.
+type ResourceThresholds struct {
+	Goroutines int
+	HeapAlloc  uint64
+	OpenFDs    int
+}
+
+type ResourceMonitorOptions struct {
+	Interval   time.Duration
+	Thresholds ResourceThresholds
+	// OnSample receives every sample, e.g. to export it as metrics.
+	OnSample func(stats ResourceStats)
+	// OnThreshold is called when a resource crosses its threshold, once per crossing,
+	// with descriptions of the exceeded limits. Usually wired to alerting.
+	OnThreshold func(stats ResourceStats, exceeded []string)
+}
+
+// ResourceMonitor periodically samples runtime resource usage, see StartResourceMonitor.
+type ResourceMonitor struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// SampleResources returns current goroutine count, heap usage and open file descriptors.
+func SampleResources() ResourceStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return ResourceStats{
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  mem.HeapAlloc,
+		HeapSys:    mem.HeapSys,
+		OpenFDs:    openFDs(),
+		SampledAt:  time.Now(),
+	}
+}
+
+// StartResourceMonitor samples resources every opts.Interval until Stop is called or ctx is done.
+func StartResourceMonitor(ctx context.Context, opts ResourceMonitorOptions) (*ResourceMonitor, error) {
+	if opts.Interval <= 0 {
+		return nil, fmt.Errorf("resource monitor interval must be positive: %s", opts.Interval)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := &ResourceMonitor{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		exceeded := map[string]bool{}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				stats := SampleResources()
+				if opts.OnSample != nil {
+					opts.OnSample(stats)
+				}
+				crossed := opts.Thresholds.crossed(stats, exceeded)
+				if len(crossed) > 0 && opts.OnThreshold != nil {
+					opts.OnThreshold(stats, crossed)
+				}
+			}
+		}
+	}()
+
+	return m, nil
+}
+
+// Stop stops sampling and waits for the monitor goroutine to exit.
+func (m *ResourceMonitor) Stop() {
+	m.cancel()
+	<-m.done
+}
+
+// crossed returns limits exceeded by stats that were not exceeded by the previous sample.
+// exceeded carries the state between samples.
+func (t ResourceThresholds) crossed(stats ResourceStats, exceeded map[string]bool) []string {
+	crossed := []string{}
+	check := func(name string, over bool, description string) {
+		if over && !exceeded[name] {
+			crossed = append(crossed, description)
+		}
+		exceeded[name] = over
+	}
+
+	check("goroutines", t.Goroutines > 0 && stats.Goroutines > t.Goroutines,
+		fmt.Sprintf("goroutines %d > %d", stats.Goroutines, t.Goroutines))
+	check("heap", t.HeapAlloc > 0 && stats.HeapAlloc > t.HeapAlloc,
+		fmt.Sprintf("heap %d bytes > %d bytes", stats.HeapAlloc, t.HeapAlloc))
+	check("fds", t.OpenFDs > 0 && stats.OpenFDs > t.OpenFDs,
+		fmt.Sprintf("open fds %d > %d", stats.OpenFDs, t.OpenFDs))
+
+	return crossed
+}
+
+func openFDs() int {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(entries)
+}
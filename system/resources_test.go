@@ -0,0 +1,72 @@
+package system
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleResources(t *testing.T) {
+	stats := SampleResources()
+
+	assert.Positive(t, stats.Goroutines)
+	assert.Positive(t, stats.HeapAlloc)
+	if runtime.GOOS == "linux" {
+		assert.Positive(t, stats.OpenFDs)
+	}
+}
+
+func TestResourceThresholds_Crossed(t *testing.T) {
+	// given
+	thresholds := ResourceThresholds{Goroutines: 10, OpenFDs: 100}
+	exceeded := map[string]bool{}
+
+	// then
+	assert.Empty(t, thresholds.crossed(ResourceStats{Goroutines: 5, OpenFDs: 5}, exceeded))
+	assert.Equal(t, []string{"goroutines 11 > 10"}, thresholds.crossed(ResourceStats{Goroutines: 11, OpenFDs: 5}, exceeded))
+	assert.Empty(t, thresholds.crossed(ResourceStats{Goroutines: 12, OpenFDs: 5}, exceeded), "still exceeded, already reported")
+	assert.Empty(t, thresholds.crossed(ResourceStats{Goroutines: 5, OpenFDs: 5}, exceeded))
+	assert.Equal(t, []string{"goroutines 11 > 10", "open fds 101 > 100"}, thresholds.crossed(ResourceStats{Goroutines: 11, OpenFDs: 101}, exceeded))
+}
+
+func TestStartResourceMonitor(t *testing.T) {
+	// given
+	var mu sync.Mutex
+	samples := 0
+	alerts := 0
+	opts := ResourceMonitorOptions{
+		Interval:   10 * time.Millisecond,
+		Thresholds: ResourceThresholds{Goroutines: 1},
+		OnSample: func(stats ResourceStats) {
+			mu.Lock()
+			defer mu.Unlock()
+			samples++
+		},
+		OnThreshold: func(stats ResourceStats, exceeded []string) {
+			mu.Lock()
+			defer mu.Unlock()
+			alerts++
+		},
+	}
+
+	// when
+	m, err := StartResourceMonitor(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("StartResourceMonitor failed: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	m.Stop()
+
+	// then
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Greater(t, samples, 1)
+	assert.Equal(t, 1, alerts)
+
+	_, err = StartResourceMonitor(context.Background(), ResourceMonitorOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os/signal"
+)
+
+// Runnable is a long-running application component: a poller, a server, a worker.
+// Run blocks until ctx is done or the component fails.
+type Runnable interface {
+	Run(ctx context.Context) error
+}
+
+type RunnableFunc func(ctx context.Context) error
+
+func (f RunnableFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+type runningComponent struct {
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// Run starts components and blocks until SIGINT/SIGTERM, until ctx is done or until a component
+// fails. Then it stops components in reverse order, canceling each one's context and waiting for
+// it to return before stopping the previous one, so list dependencies first: Run(ctx, workers, poller)
+// stops the poller before draining the workers. A component returning nil before shutdown is considered finished and doesn't stop the others.
+// Run returns the error that caused the shutdown joined with errors returned during it;
+// context.Canceled is not treated as an error.
+func Run(ctx context.Context, components ...Runnable) error {
+	signalCtx, stop := signal.NotifyContext(ctx, shutdownSignals...)
+	defer stop()
+
+	failed := make(chan int, len(components))
+	running := make([]runningComponent, len(components))
+	for i, component := range components {
+		componentCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		done := make(chan error, 1)
+		running[i] = runningComponent{cancel: cancel, done: done}
+		go func(i int, component Runnable) {
+			err := component.Run(componentCtx)
+			done <- err
+			if err != nil {
+				failed <- i
+			}
+		}(i, component)
+	}
+
+	var errs []error
+	first := -1
+	select {
+	case <-signalCtx.Done():
+		log.Println("Shutdown signal received")
+	case first = <-failed:
+		errs = append(errs, <-running[first].done)
+	}
+
+	for i := len(running) - 1; i >= 0; i-- {
+		running[i].cancel()
+		if i == first {
+			continue
+		}
+		err := <-running[i].done
+		if err != nil && !errors.Is(err, context.Canceled) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
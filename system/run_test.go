@@ -0,0 +1,92 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stopRecorder struct {
+	mu      sync.Mutex
+	stopped []string
+}
+
+func (r *stopRecorder) component(name string, err error) Runnable {
+	return RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.stopped = append(r.stopped, name)
+		if err != nil {
+			return err
+		}
+		return ctx.Err()
+	})
+}
+
+func TestRun_StopsInReverseOrderOnContextDone(t *testing.T) {
+	// given
+	rec := &stopRecorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	// when
+	err := Run(ctx, rec.component("workers", nil), rec.component("poller", nil))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"poller", "workers"}, rec.stopped)
+}
+
+func TestRun_StopsOnSignal(t *testing.T) {
+	// given
+	rec := &stopRecorder{}
+	time.AfterFunc(20*time.Millisecond, func() { sendSignal(t, syscall.SIGTERM) })
+
+	// when
+	err := Run(context.Background(), rec.component("workers", nil))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"workers"}, rec.stopped)
+}
+
+func TestRun_StopsOnFirstFailure(t *testing.T) {
+	// given
+	rec := &stopRecorder{}
+	pollErr := errors.New("poll failed")
+	closeErr := errors.New("close failed")
+	failing := RunnableFunc(func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return pollErr
+	})
+	finished := RunnableFunc(func(ctx context.Context) error { return nil })
+
+	// when
+	err := Run(context.Background(), rec.component("db", closeErr), finished, failing, rec.component("health", nil))
+
+	// then
+	assert.ErrorIs(t, err, pollErr)
+	assert.ErrorIs(t, err, closeErr)
+	assert.Equal(t, []string{"health", "db"}, rec.stopped)
+}
+
+func TestHealthServer_Run(t *testing.T) {
+	// given
+	h := NewHealthServer("127.0.0.1:0")
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	// when
+	err := Run(ctx, h)
+
+	// then
+	assert.NoError(t, err)
+	assert.NoError(t, h.Start(), "server is stopped and can be started again")
+	h.Stop(context.Background())
+}
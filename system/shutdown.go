@@ -0,0 +1,55 @@
+// Package system contains process lifecycle helpers for long-running services.
+package system
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// ShutdownContext returns a context canceled on SIGINT or SIGTERM, so shutdown can be plumbed
+// through the call graph. stop releases the signal handler; after it's called a second signal
+// terminates the process as usual.
+func ShutdownContext() (ctx context.Context, stop func()) {
+	return ShutdownContextFor(shutdownSignals...)
+}
+
+// ShutdownContextFor is like ShutdownContext but canceled on the given signals instead.
+func ShutdownContextFor(signals ...os.Signal) (ctx context.Context, stop func()) {
+	return signal.NotifyContext(context.Background(), signals...)
+}
+
+// OnReload calls reload on every SIGHUP until ctx is done or stop is called, so services can
+// re-read configuration without restarting. Calls are sequential; signals arriving while reload
+// runs are coalesced into one call. stop waits for a running reload to return.
+func OnReload(ctx context.Context, reload func()) (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+				reload()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigs)
+			cancel()
+			<-done
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package system
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownContext(t *testing.T) {
+	// given
+	ctx, stop := ShutdownContext()
+	defer stop()
+	assert.NoError(t, ctx.Err())
+
+	// when
+	sendSignal(t, syscall.SIGTERM)
+
+	// then
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not canceled on SIGTERM")
+	}
+}
+
+func TestShutdownContextFor(t *testing.T) {
+	// given
+	ctx, stop := ShutdownContextFor(os.Interrupt)
+	defer stop()
+
+	// when
+	sendSignal(t, os.Interrupt)
+
+	// then
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not canceled on SIGINT")
+	}
+}
+
+func TestOnReload(t *testing.T) {
+	// given
+	reloads := make(chan struct{}, 1)
+	stop := OnReload(context.Background(), func() {
+		reloads <- struct{}{}
+	})
+	defer stop()
+
+	// when
+	sendSignal(t, syscall.SIGHUP)
+
+	// then
+	select {
+	case <-reloads:
+	case <-time.After(time.Second):
+		t.Fatal("reload was not called on SIGHUP")
+	}
+
+	stop()
+	stop()
+}
+
+func sendSignal(t *testing.T, sig os.Signal) {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("FindProcess failed: %v", err)
+	}
+	err = p.Signal(sig)
+	if err != nil {
+		t.Skipf("sending signals is not supported: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CloseFunc releases a component. It should return promptly once ctx is done.
+type CloseFunc func(ctx context.Context) error
+
+type closer struct {
+	name     string
+	priority int
+	order    int
+	fn       CloseFunc
+}
+
+// Shutdowner closes registered components in priority order within a global timeout.
+type Shutdowner struct {
+	mu      sync.Mutex
+	closers []closer
+	timeout time.Duration
+}
+
+func NewShutdowner(timeout time.Duration) *Shutdowner {
+	return &Shutdowner{
+		timeout: timeout,
+	}
+}
+
+// Register adds a component to close. Lower priorities close first, e.g. stop accepting updates (0),
+// then drain workers (10), then close the database (100). Components with equal priority close
+// in reverse registration order, like deferred calls.
+func (s *Shutdowner) Register(name string, priority int, fn CloseFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closers = append(s.closers, closer{
+		name:     name,
+		priority: priority,
+		order:    len(s.closers),
+		fn:       fn,
+	})
+}
+
+// Wait blocks until SIGINT/SIGTERM or until ctx is done, then calls Shutdown.
+func (s *Shutdowner) Wait(ctx context.Context) error {
+	signalCtx, stop := ShutdownContext()
+	defer stop()
+
+	select {
+	case <-signalCtx.Done():
+		log.Println("Shutdown signal received")
+	case <-ctx.Done():
+	}
+	return s.Shutdown(context.Background())
+}
+
+// Shutdown runs every registered closer once, sequentially, and returns their errors joined.
+// When the timeout expires, the running closer is abandoned and the remaining ones are reported as skipped.
+func (s *Shutdowner) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	closers := s.closers
+	s.closers = nil
+	s.mu.Unlock()
+
+	sort.SliceStable(closers, func(i, j int) bool {
+		if closers[i].priority != closers[j].priority {
+			return closers[i].priority < closers[j].priority
+		}
+		return closers[i].order > closers[j].order
+	})
+
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	errs := []error{}
+	for _, c := range closers {
+		err := runCloser(ctx, c)
+		if err != nil {
+			log.Println("Shutdown of", c.name, "failed:", err)
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func runCloser(ctx context.Context, c closer) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("skipped: %w", ctx.Err())
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,92 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdowner_Order(t *testing.T) {
+	// given
+	s := NewShutdowner(time.Second)
+	order := []string{}
+	record := func(name string) CloseFunc {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+	s.Register("db", 100, record("db"))
+	s.Register("poller", 0, record("poller"))
+	s.Register("worker 1", 10, record("worker 1"))
+	s.Register("worker 2", 10, record("worker 2"))
+
+	// when
+	err := s.Shutdown(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"poller", "worker 2", "worker 1", "db"}, order)
+	assert.NoError(t, s.Shutdown(context.Background()), "closers run only once")
+	assert.Len(t, order, 4)
+}
+
+func TestShutdowner_Errors(t *testing.T) {
+	// given
+	s := NewShutdowner(50 * time.Millisecond)
+	s.Register("failing", 0, func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+	s.Register("hanging", 1, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	lateCalled := false
+	s.Register("late", 2, func(ctx context.Context) error {
+		lateCalled = true
+		return nil
+	})
+
+	// when
+	start := time.Now()
+	err := s.Shutdown(context.Background())
+
+	// then
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "failing: boom")
+	assert.ErrorContains(t, err, "hanging: context deadline exceeded")
+	assert.ErrorContains(t, err, "late: skipped")
+	assert.False(t, lateCalled)
+}
+
+func TestShutdowner_WaitForSignal(t *testing.T) {
+	// given
+	s := NewShutdowner(time.Second)
+	closed := make(chan struct{})
+	s.Register("component", 0, func(ctx context.Context) error {
+		close(closed)
+		return nil
+	})
+	done := make(chan error)
+	go func() {
+		done <- s.Wait(context.Background())
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// when
+	sendSignal(t, syscall.SIGTERM)
+
+	// then
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Wait didn't return after SIGTERM")
+	}
+	<-closed
+}
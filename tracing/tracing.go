@@ -0,0 +1,139 @@
+// Package tracing adds optional OpenTelemetry spans and trace context propagation to HTTP
+// clients, HTTP servers and sqldb. It's a separate package so that the others don't depend on
+// OpenTelemetry.
+//
+// Spans are created with the global tracer provider and propagated with the global propagator,
+// so the application configures the SDK once:
+//
+//	otel.SetTracerProvider(provider)
+//	otel.SetTextMapPropagator(propagation.TraceContext{})
+//
+//	client.Transport = tracing.Transport(client.Transport)
+//	srv := httpserver.New(tracing.Middleware(mux), httpserver.DefaultOptions(":8080"))
+//	db.SetQueryHook(tracing.QueryHook(sqldb.LogQueryHook), time.Second)
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/httpclient"
+	"github.com/denis-kilchichakov/toolbox/httpserver"
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/denis-kilchichakov/toolbox/tracing"
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Transport starts a client span per request and injects the trace context into its headers.
+// Wrapping an httpclient transport yields one span per request, covering all retry attempts.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx, span := tracer().Start(req.Context(), "HTTP "+req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.full", redactURL(req)),
+				attribute.String("server.address", req.URL.Hostname()),
+			),
+		)
+		defer span.End()
+
+		req = req.Clone(ctx)
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+		return resp, nil
+	})
+}
+
+// Middleware continues the trace of incoming requests, starting a server span per request.
+// Inside httpserver.New the span carries the request ID.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer().Start(ctx, "HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+		if id := httpserver.RequestIDFromContext(ctx); id != "" {
+			span.SetAttributes(attribute.String("http.request.id", id))
+		}
+
+		rec := httpserver.NewStatusRecorder(w)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.Status))
+		if rec.Status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(rec.Status))
+		}
+	})
+}
+
+// QueryHook returns a sqldb.QueryHook recording a span per statement and then calling next,
+// which may be nil. Query arguments are not recorded.
+func QueryHook(next sqldb.QueryHook) sqldb.QueryHook {
+	return func(ctx context.Context, event sqldb.QueryEvent) {
+		end := time.Now()
+		operation := queryOperation(event.Query)
+		_, span := tracer().Start(ctx, "SQL "+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(end.Add(-event.Duration)),
+			trace.WithAttributes(
+				attribute.String("db.operation.name", operation),
+				attribute.String("db.query.text", event.Query),
+			),
+		)
+		if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+			span.RecordError(event.Err)
+			span.SetStatus(codes.Error, event.Err.Error())
+		}
+		span.End(trace.WithTimestamp(end))
+
+		if next != nil {
+			next(ctx, event)
+		}
+	}
+}
+
+// redactURL drops the query string and credentials, which often carry API keys.
+func redactURL(req *http.Request) string {
+	return fmt.Sprintf("%s://%s%s", req.URL.Scheme, req.URL.Host, req.URL.EscapedPath())
+}
+
+func queryOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	return strings.ToLower(strings.TrimLeft(fields[0], "("))
+}
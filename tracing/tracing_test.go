@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/httpclient"
+	"github.com/denis-kilchichakov/toolbox/httpserver"
+	"github.com/denis-kilchichakov/toolbox/sqldb"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestTransportAndMiddlewarePropagateTrace(t *testing.T) {
+	// given
+	recorder := setupTracing(t)
+	var serverSpan trace.SpanContext
+	handler := httpserver.Chain(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	})), httpserver.RequestID)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	client := &http.Client{Transport: Transport(nil)}
+
+	// when
+	resp, err := client.Get(srv.URL + "/brew?key=secret")
+
+	// then
+	assert.NoError(t, err)
+	resp.Body.Close()
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	server, clientSpan := spans[0], spans[1]
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, trace.SpanKindClient, clientSpan.SpanKind())
+	assert.Equal(t, clientSpan.SpanContext().TraceID(), server.SpanContext().TraceID())
+	assert.Equal(t, clientSpan.SpanContext().SpanID(), server.Parent().SpanID())
+	assert.Equal(t, server.SpanContext().SpanID(), serverSpan.SpanID())
+	assert.Equal(t, codes.Error, clientSpan.Status().Code)
+	assert.Equal(t, codes.Unset, server.Status().Code)
+
+	attrs := map[string]string{}
+	for _, kv := range append(clientSpan.Attributes(), server.Attributes()...) {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, srv.URL+"/brew", attrs["url.full"])
+	assert.Equal(t, "418", attrs["http.response.status_code"])
+	assert.Len(t, attrs["http.request.id"], 26)
+}
+
+func TestTransportRecordsErrors(t *testing.T) {
+	// given
+	recorder := setupTracing(t)
+	failing := Transport(httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}))
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+
+	// when
+	_, err := failing.RoundTrip(req)
+
+	// then
+	assert.Error(t, err)
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "connection refused", spans[0].Status().Description)
+}
+
+func TestQueryHook(t *testing.T) {
+	// given
+	recorder := setupTracing(t)
+	var called []sqldb.QueryEvent
+	hook := QueryHook(func(ctx context.Context, event sqldb.QueryEvent) {
+		called = append(called, event)
+	})
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "handler")
+
+	// when
+	hook(ctx, sqldb.QueryEvent{Query: "SELECT v FROM kv WHERE k = $1", Duration: 20 * time.Millisecond, Err: sql.ErrNoRows})
+	hook(ctx, sqldb.QueryEvent{Query: "INSERT INTO kv VALUES ($1)", Err: errors.New("locked")})
+	parent.End()
+
+	// then
+	assert.Len(t, called, 2)
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	sel, ins := spans[0], spans[1]
+	assert.Equal(t, "SQL select", sel.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), sel.Parent().SpanID())
+	assert.Equal(t, 20*time.Millisecond, sel.EndTime().Sub(sel.StartTime()))
+	assert.Equal(t, codes.Unset, sel.Status().Code)
+	assert.Equal(t, "SQL insert", ins.Name())
+	assert.Equal(t, codes.Error, ins.Status().Code)
+}
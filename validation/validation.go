@@ -0,0 +1,266 @@
+// Package validation checks struct fields against `validate` tags:
+//
+//	type Request struct {
+//		Model       string   `validate:"required,oneof=small large"`
+//		Temperature float64  `validate:"min=0,max=2"`
+//		Email       string   `validate:"email"`
+//		Tags        []string `validate:"max=10"`
+//	}
+//
+// Supported rules:
+//
+//	required     the value is not zero (nil pointers, empty strings, slices and maps fail)
+//	min=N, max=N bounds for numbers and durations ("30s"), length bounds for strings, slices and maps
+//	oneof=a b c  the value is one of the space-separated options
+//	email, url   the string is an e-mail address or an absolute URL
+//	regexp=RE    the string matches RE; must be the last rule since RE may contain commas
+//
+// oneof, email, url and regexp skip empty values, so combine them with required when needed.
+// Nested structs, pointers to structs and slices of structs are validated recursively.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+)
+
+// FieldError describes a single failed rule. Field is a path like "Items[2].Name".
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// Errors aggregates all field errors of a struct.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is matches errs.Validation.
+func (e Errors) Is(target error) bool {
+	return target == errs.Validation
+}
+
+// Struct validates v, a struct or a pointer to a struct, and returns Errors or nil.
+// Malformed tags are reported as errors of the field they belong to.
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("validation of nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validation of non-struct %T", v)
+	}
+
+	var errs Errors
+	validateStruct(rv, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(v reflect.Value, prefix string, errs *Errors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		path := prefix + sf.Name
+		fv := v.Field(i)
+		if tag := sf.Tag.Get("validate"); tag != "" && tag != "-" {
+			validateField(fv, path, tag, errs)
+		}
+		validateNested(fv, path, errs)
+	}
+}
+
+func validateNested(v reflect.Value, path string, errs *Errors) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			validateNested(v.Elem(), path, errs)
+		}
+	case reflect.Struct:
+		if v.Type() != reflect.TypeOf(time.Time{}) {
+			validateStruct(v, path+".", errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateNested(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func validateField(v reflect.Value, path, tag string, errs *Errors) {
+	for _, rule := range splitRules(tag) {
+		name, param, _ := strings.Cut(rule, "=")
+		msg, err := check(v, name, param)
+		if err != nil {
+			msg = "has invalid rule " + strconv.Quote(rule) + ": " + err.Error()
+		}
+		if msg != "" {
+			*errs = append(*errs, FieldError{Field: path, Rule: name, Message: msg})
+		}
+	}
+}
+
+func splitRules(tag string) []string {
+	var rules []string
+	for tag != "" {
+		if strings.HasPrefix(tag, "regexp=") {
+			return append(rules, tag)
+		}
+		rule, rest, _ := strings.Cut(tag, ",")
+		rules = append(rules, strings.TrimSpace(rule))
+		tag = rest
+	}
+	return rules
+}
+
+// check returns a message for a failed rule, or an error for a rule which doesn't apply to v.
+func check(v reflect.Value, rule, param string) (string, error) {
+	if rule == "required" {
+		if v.IsZero() {
+			return "is required", nil
+		}
+		return "", nil
+	}
+
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	switch rule {
+	case "min", "max":
+		return checkBound(v, rule, param)
+	case "oneof":
+		if v.IsZero() {
+			return "", nil
+		}
+		s := fmt.Sprint(v.Interface())
+		for _, option := range strings.Fields(param) {
+			if s == option {
+				return "", nil
+			}
+		}
+		return "must be one of " + strings.Join(strings.Fields(param), ", "), nil
+	case "email":
+		s, err := stringValue(v)
+		if err != nil || s == "" {
+			return "", err
+		}
+		addr, err := mail.ParseAddress(s)
+		if err != nil || addr.Address != s {
+			return "must be a valid e-mail address", nil
+		}
+		return "", nil
+	case "url":
+		s, err := stringValue(v)
+		if err != nil || s == "" {
+			return "", err
+		}
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be an absolute URL", nil
+		}
+		return "", nil
+	case "regexp":
+		s, err := stringValue(v)
+		if err != nil {
+			return "", err
+		}
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return "", err
+		}
+		if s != "" && !re.MatchString(s) {
+			return "must match " + param, nil
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown rule")
+}
+
+func checkBound(v reflect.Value, rule, param string) (string, error) {
+	fail := func(actual, bound float64) bool {
+		if rule == "min" {
+			return actual < bound
+		}
+		return actual > bound
+	}
+	relation := map[string]string{"min": "at least", "max": "at most"}[rule]
+
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		bound, err := time.ParseDuration(param)
+		if err != nil {
+			return "", err
+		}
+		if fail(float64(v.Int()), float64(bound)) {
+			return "must be " + relation + " " + bound.String(), nil
+		}
+		return "", nil
+	}
+
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return "", err
+	}
+	var actual float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = v.Float()
+	case reflect.String:
+		if fail(float64(len([]rune(v.String()))), bound) {
+			return fmt.Sprintf("must be %s %s characters long", relation, param), nil
+		}
+		return "", nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if fail(float64(v.Len()), bound) {
+			return fmt.Sprintf("must have %s %s elements", relation, param), nil
+		}
+		return "", nil
+	default:
+		return "", fmt.Errorf("not applicable to %s", v.Type())
+	}
+	if fail(actual, bound) {
+		return "must be " + relation + " " + param, nil
+	}
+	return "", nil
+}
+
+func stringValue(v reflect.Value) (string, error) {
+	if v.Kind() != reflect.String {
+		return "", fmt.Errorf("not applicable to %s", v.Type())
+	}
+	return v.String(), nil
+}
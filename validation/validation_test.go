@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	Name string `validate:"required"`
+}
+
+type request struct {
+	Model       string        `validate:"required,oneof=small large"`
+	Temperature float64       `validate:"min=0,max=2"`
+	MaxTokens   *int          `validate:"min=1"`
+	Timeout     time.Duration `validate:"max=1m"`
+	Email       string        `validate:"email"`
+	Callback    string        `validate:"url"`
+	Code        string        `validate:"regexp=^[a-z]{2,3}$"`
+	Tags        []string      `validate:"max=2"`
+	Items       []item
+	Owner       *item
+}
+
+func TestStructValid(t *testing.T) {
+	// given
+	tokens := 100
+	r := request{
+		Model:       "small",
+		Temperature: 0.7,
+		MaxTokens:   &tokens,
+		Timeout:     time.Second,
+		Email:       "bot@example.com",
+		Callback:    "https://example.com/hook",
+		Code:        "en",
+		Items:       []item{{Name: "a"}},
+	}
+
+	// when
+	err := Struct(&r)
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestStructAggregatesErrors(t *testing.T) {
+	// given
+	tokens := 0
+	r := request{
+		Model:       "huge",
+		Temperature: 3,
+		MaxTokens:   &tokens,
+		Timeout:     time.Hour,
+		Email:       "Bot <bot@example.com>",
+		Callback:    "/hook",
+		Code:        "english",
+		Tags:        []string{"a", "b", "c"},
+		Items:       []item{{Name: "a"}, {}},
+		Owner:       &item{},
+	}
+
+	// when
+	err := Struct(r)
+
+	// then
+	var fieldErrs Errors
+	assert.True(t, errors.As(err, &fieldErrs))
+	assert.ErrorIs(t, err, errs.Validation)
+	assert.Equal(t, Errors{
+		{Field: "Model", Rule: "oneof", Message: "must be one of small, large"},
+		{Field: "Temperature", Rule: "max", Message: "must be at most 2"},
+		{Field: "MaxTokens", Rule: "min", Message: "must be at least 1"},
+		{Field: "Timeout", Rule: "max", Message: "must be at most 1m0s"},
+		{Field: "Email", Rule: "email", Message: "must be a valid e-mail address"},
+		{Field: "Callback", Rule: "url", Message: "must be an absolute URL"},
+		{Field: "Code", Rule: "regexp", Message: "must match ^[a-z]{2,3}$"},
+		{Field: "Tags", Rule: "max", Message: "must have at most 2 elements"},
+		{Field: "Items[1].Name", Rule: "required", Message: "is required"},
+		{Field: "Owner.Name", Rule: "required", Message: "is required"},
+	}, fieldErrs)
+	assert.Contains(t, err.Error(), "Model must be one of small, large; Temperature must be at most 2")
+}
+
+func TestStructSkipsEmptyOptionalValues(t *testing.T) {
+	// given
+	r := request{Model: ""}
+
+	// when
+	err := Struct(&r)
+
+	// then
+	assert.EqualError(t, err, "Model is required")
+}
+
+func TestStructReportsInvalidRules(t *testing.T) {
+	// given
+	var v struct {
+		Enabled bool   `validate:"min=1"`
+		Name    string `validate:"unique"`
+	}
+
+	// when
+	err := Struct(&v)
+
+	// then
+	assert.EqualError(t, err, `Enabled has invalid rule "min=1": not applicable to bool; Name has invalid rule "unique": unknown rule`)
+}
+
+func TestStructRejectsNonStruct(t *testing.T) {
+	assert.Error(t, Struct(42))
+	assert.Error(t, Struct((*request)(nil)))
+}
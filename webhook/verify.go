@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/signing"
+)
+
+var (
+	ErrInvalidSignature = signing.ErrInvalidSignature
+	ErrExpired          = errors.New("webhook timestamp is outside of tolerance")
+	// ErrEmptySecret is returned by every Verify call of a verifier built with an empty secret,
+	// which would otherwise accept deliveries anyone can sign.
+	ErrEmptySecret = errors.New("webhook secret is empty")
+)
+
+// DefaultTolerance is the accepted age of timestamped signatures.
+const DefaultTolerance = 5 * time.Minute
+
+// Verifier authenticates a delivery and extracts its metadata. Verification errors are answered
+// with 401 Unauthorized.
+type Verifier interface {
+	Verify(r *http.Request, body []byte) (Event, error)
+}
+
+type VerifierFunc func(r *http.Request, body []byte) (Event, error)
+
+func (f VerifierFunc) Verify(r *http.Request, body []byte) (Event, error) {
+	return f(r, body)
+}
+
+type HMACOptions struct {
+	Secret string
+	// SignatureHeader carries the hex-encoded HMAC-SHA256 of the body, optionally after Prefix.
+	SignatureHeader string
+	Prefix          string
+	// IDHeader and EventHeader optionally carry the delivery ID and the event type.
+	IDHeader    string
+	EventHeader string
+}
+
+// HMAC verifies a hex-encoded HMAC-SHA256 signature of the body sent in a header.
+func HMAC(opts HMACOptions) Verifier {
+	if opts.Secret == "" {
+		return rejectAll()
+	}
+	return VerifierFunc(func(r *http.Request, body []byte) (Event, error) {
+		signature := r.Header.Get(opts.SignatureHeader)
+		if !strings.HasPrefix(signature, opts.Prefix) {
+			return Event{}, ErrInvalidSignature
+		}
+		if !signing.Verify([]byte(opts.Secret), body, strings.TrimPrefix(signature, opts.Prefix)) {
+			return Event{}, ErrInvalidSignature
+		}
+
+		e := Event{Body: body, Header: r.Header}
+		if opts.IDHeader != "" {
+			e.ID = r.Header.Get(opts.IDHeader)
+		}
+		if opts.EventHeader != "" {
+			e.Type = r.Header.Get(opts.EventHeader)
+		}
+		return e, nil
+	})
+}
+
+// GitHub verifies X-Hub-Signature-256 and takes the type from X-GitHub-Event, e.g. "push".
+func GitHub(secret string) Verifier {
+	return HMAC(HMACOptions{
+		Secret:          secret,
+		SignatureHeader: "X-Hub-Signature-256",
+		Prefix:          "sha256=",
+		IDHeader:        "X-GitHub-Delivery",
+		EventHeader:     "X-GitHub-Event",
+	})
+}
+
+// Stripe verifies the Stripe-Signature header, rejecting signatures older than tolerance
+// (DefaultTolerance if zero), and takes the ID and type from the event body.
+func Stripe(secret string, tolerance time.Duration) Verifier {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	return stripeVerifier(secret, tolerance, time.Now)
+}
+
+func stripeVerifier(secret string, tolerance time.Duration, now func() time.Time) Verifier {
+	if secret == "" {
+		return rejectAll()
+	}
+	return VerifierFunc(func(r *http.Request, body []byte) (Event, error) {
+		var timestamp string
+		var signatures []string
+		for _, part := range strings.Split(r.Header.Get("Stripe-Signature"), ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch key {
+			case "t":
+				timestamp = value
+			case "v1":
+				signatures = append(signatures, value)
+			}
+		}
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return Event{}, ErrInvalidSignature
+		}
+
+		valid := false
+		for _, signature := range signatures {
+			if signing.Verify([]byte(secret), []byte(timestamp+"."+string(body)), signature) {
+				valid = true
+			}
+		}
+		if !valid {
+			return Event{}, ErrInvalidSignature
+		}
+		if age := now().Sub(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+			return Event{}, ErrExpired
+		}
+
+		var envelope struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return Event{}, fmt.Errorf("invalid stripe event: %w", err)
+		}
+		return Event{ID: envelope.ID, Type: envelope.Type, Body: body, Header: r.Header}, nil
+	})
+}
+
+// TelegramEventType is the type of all events verified by Telegram.
+const TelegramEventType = "update"
+
+// Telegram checks the secret token set with setWebhook and uses update_id as the event ID.
+func Telegram(secretToken string) Verifier {
+	if secretToken == "" {
+		return rejectAll()
+	}
+	return VerifierFunc(func(r *http.Request, body []byte) (Event, error) {
+		token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secretToken)) != 1 {
+			return Event{}, ErrInvalidSignature
+		}
+
+		var update struct {
+			UpdateID int64 `json:"update_id"`
+		}
+		if err := json.Unmarshal(body, &update); err != nil {
+			return Event{}, fmt.Errorf("invalid telegram update: %w", err)
+		}
+		return Event{
+			ID:     strconv.FormatInt(update.UpdateID, 10),
+			Type:   TelegramEventType,
+			Body:   body,
+			Header: r.Header,
+		}, nil
+	})
+}
+
+func rejectAll() Verifier {
+	return VerifierFunc(func(r *http.Request, body []byte) (Event, error) {
+		return Event{}, ErrEmptySecret
+	})
+}
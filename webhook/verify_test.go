@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/signing"
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(secret, data string) string {
+	return signing.Sign([]byte(secret), []byte(data))
+}
+
+func TestGitHub(t *testing.T) {
+	// given
+	body := `{"ref":"refs/heads/main"}`
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", "sha256="+sign("secret", body))
+	req.Header.Set("X-GitHub-Delivery", "d-1")
+	req.Header.Set("X-GitHub-Event", "push")
+
+	// when
+	e, err := GitHub("secret").Verify(req, []byte(body))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "d-1", e.ID)
+	assert.Equal(t, "push", e.Type)
+	assert.Equal(t, body, string(e.Body))
+}
+
+func TestGitHubInvalidSignature(t *testing.T) {
+	for _, signature := range []string{"", sign("secret", "{}"), "sha256=" + sign("other", "{}"), "sha256=zz"} {
+		// given
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("X-Hub-Signature-256", signature)
+
+		// when
+		_, err := GitHub("secret").Verify(req, []byte("{}"))
+
+		// then
+		assert.ErrorIs(t, err, ErrInvalidSignature, signature)
+	}
+}
+
+func TestStripe(t *testing.T) {
+	// given
+	now := time.Unix(1_700_000_000, 0)
+	body := `{"id":"evt_1","type":"invoice.paid"}`
+	header := fmt.Sprintf("t=%d,v1=%s,v1=%s", now.Unix(), sign("old", "x"), sign("whsec", fmt.Sprintf("%d.%s", now.Unix(), body)))
+	v := stripeVerifier("whsec", DefaultTolerance, func() time.Time { return now.Add(time.Minute) })
+
+	// when
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Stripe-Signature", header)
+	e, err := v.Verify(req, []byte(body))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "evt_1", e.ID)
+	assert.Equal(t, "invoice.paid", e.Type)
+}
+
+func TestStripeRejectsOldAndInvalidSignatures(t *testing.T) {
+	// given
+	now := time.Unix(1_700_000_000, 0)
+	body := `{"id":"evt_1","type":"invoice.paid"}`
+	valid := fmt.Sprintf("t=%d,v1=%s", now.Unix(), sign("whsec", fmt.Sprintf("%d.%s", now.Unix(), body)))
+	v := stripeVerifier("whsec", DefaultTolerance, func() time.Time { return now.Add(10 * time.Minute) })
+
+	for header, expected := range map[string]error{
+		valid:                                 ErrExpired,
+		"t=abc,v1=00":                         ErrInvalidSignature,
+		fmt.Sprintf("t=%d,v1=00", now.Unix()): ErrInvalidSignature,
+	} {
+		// when
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("Stripe-Signature", header)
+		_, err := v.Verify(req, []byte(body))
+
+		// then
+		assert.ErrorIs(t, err, expected, header)
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	// given
+	body := `{"update_id":42,"message":{"text":"hi"}}`
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", "token")
+
+	// when
+	e, err := Telegram("token").Verify(req, []byte(body))
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", "wrong")
+	_, wrongErr := Telegram("token").Verify(req, []byte(body))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "42", e.ID)
+	assert.Equal(t, TelegramEventType, e.Type)
+	assert.ErrorIs(t, wrongErr, ErrInvalidSignature)
+}
+
+func TestEmptySecretRejectsEverything(t *testing.T) {
+	// given
+	body := `{"update_id":42}`
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+sign("", body))
+	req.Header.Set("Stripe-Signature", fmt.Sprintf("t=%d,v1=%s", time.Now().Unix(), sign("", fmt.Sprintf("%d.%s", time.Now().Unix(), body))))
+
+	for name, v := range map[string]Verifier{
+		"telegram": Telegram(""),
+		"github":   GitHub(""),
+		"stripe":   Stripe("", 0),
+		"hmac":     HMAC(HMACOptions{SignatureHeader: "X-Hub-Signature-256", Prefix: "sha256="}),
+	} {
+		// when
+		_, err := v.Verify(req, []byte(body))
+
+		// then
+		assert.ErrorIs(t, err, ErrEmptySecret, name)
+	}
+}
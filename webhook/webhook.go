@@ -0,0 +1,214 @@
+// Package webhook receives inbound webhooks: it verifies signatures, drops replayed deliveries
+// and dispatches payloads decoded into typed handlers.
+//
+//	rcv := webhook.New(webhook.GitHub(secret))
+//	webhook.Handle(rcv, "push", func(ctx context.Context, e webhook.Event, push PushEvent) error {
+//		...
+//	})
+//	srv := httpserver.New(rcv, httpserver.DefaultOptions(":8080"))
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/denis-kilchichakov/toolbox/httpserver"
+	"github.com/denis-kilchichakov/toolbox/validation"
+)
+
+// Event is a verified delivery.
+type Event struct {
+	// ID identifies the delivery for replay protection, empty if the provider doesn't send one.
+	ID     string
+	Type   string
+	Body   []byte
+	Header http.Header
+}
+
+type Options struct {
+	// MaxBodySize limits request bodies, larger ones are rejected with 413.
+	MaxBodySize int64
+	// ReplayWindow is how long delivery IDs are remembered. Repeated deliveries within it are
+	// acknowledged without dispatching. Zero disables replay protection.
+	ReplayWindow time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MaxBodySize:  1 << 20,
+		ReplayWindow: 24 * time.Hour,
+	}
+}
+
+type handlerFunc func(ctx context.Context, e Event) error
+
+// Receiver is an http.Handler for webhook deliveries. Responses follow what providers expect:
+// 401 for failed verification, 400 for undecodable or invalid payloads, 500 for handler errors
+// so that the provider retries, and 200 otherwise, including events without a handler.
+type Receiver struct {
+	verifier Verifier
+	opts     Options
+	now      func() time.Time
+
+	mu        sync.Mutex
+	handlers  map[string]handlerFunc
+	seen      map[string]time.Time
+	lastPrune time.Time
+}
+
+func New(verifier Verifier) *Receiver {
+	return NewWithOptions(verifier, DefaultOptions())
+}
+
+func NewWithOptions(verifier Verifier, opts Options) *Receiver {
+	return &Receiver{
+		verifier: verifier,
+		opts:     opts,
+		now:      time.Now,
+		handlers: map[string]handlerFunc{},
+		seen:     map[string]time.Time{},
+	}
+}
+
+// errBadPayload marks payload decoding and validation errors.
+type errBadPayload struct {
+	err error
+}
+
+func (e errBadPayload) Error() string {
+	return "bad webhook payload: " + e.err.Error()
+}
+
+func (e errBadPayload) Unwrap() error {
+	return e.err
+}
+
+// Handle registers fn for events of eventType, or for events without a more specific handler if
+// eventType is "". The body is decoded from JSON into T, use json.RawMessage to get it as is.
+// Struct payloads are checked against `validate` tags, see package validation.
+func Handle[T any](rcv *Receiver, eventType string, fn func(ctx context.Context, e Event, payload T) error) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	rcv.handlers[eventType] = func(ctx context.Context, e Event) error {
+		var payload T
+		if err := json.Unmarshal(e.Body, &payload); err != nil {
+			return errBadPayload{err}
+		}
+		if isStruct(payload) {
+			if err := validation.Struct(&payload); err != nil {
+				return errBadPayload{err}
+			}
+		}
+		return fn(ctx, e, payload)
+	}
+}
+
+func (rcv *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	requestID := httpserver.RequestIDFromContext(r.Context())
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, rcv.opts.MaxBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	event, err := rcv.verifier.Verify(r, body)
+	if err != nil {
+		log.Printf("Webhook %s verification failed: %v", requestID, err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	handler := rcv.handler(event.Type)
+	if handler == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !rcv.remember(event.ID) {
+		log.Printf("Webhook %s: delivery %s was already received", requestID, event.ID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err = handler(r.Context(), event)
+	if err != nil {
+		var bad errBadPayload
+		if errors.As(err, &bad) {
+			log.Printf("Webhook %s: %v", requestID, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Let the provider's retry through.
+		rcv.forget(event.ID)
+		log.Printf("Webhook %s handler for %q failed: %v", requestID, event.Type, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (rcv *Receiver) handler(eventType string) handlerFunc {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	if h, ok := rcv.handlers[eventType]; ok {
+		return h
+	}
+	return rcv.handlers[""]
+}
+
+// remember records the delivery ID and reports whether it wasn't seen within the replay window.
+func (rcv *Receiver) remember(id string) bool {
+	if id == "" || rcv.opts.ReplayWindow <= 0 {
+		return true
+	}
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	now := rcv.now()
+	if now.Sub(rcv.lastPrune) >= time.Minute {
+		for seenID, at := range rcv.seen {
+			if now.Sub(at) >= rcv.opts.ReplayWindow {
+				delete(rcv.seen, seenID)
+			}
+		}
+		rcv.lastPrune = now
+	}
+
+	if at, ok := rcv.seen[id]; ok && now.Sub(at) < rcv.opts.ReplayWindow {
+		return false
+	}
+	rcv.seen[id] = now
+	return true
+}
+
+func (rcv *Receiver) forget(id string) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	delete(rcv.seen, id)
+}
+
+func isStruct(v any) bool {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Struct
+}
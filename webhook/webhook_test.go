@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pushEvent struct {
+	Ref string `json:"ref" validate:"required"`
+}
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func deliver(rcv *Receiver, id, eventType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", "sha256="+sign("secret", body))
+	req.Header.Set("X-GitHub-Delivery", id)
+	req.Header.Set("X-GitHub-Event", eventType)
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReceiverDispatchesTypedPayload(t *testing.T) {
+	// given
+	rcv := New(GitHub("secret"))
+	var got pushEvent
+	var gotEvent Event
+	Handle(rcv, "push", func(ctx context.Context, e Event, push pushEvent) error {
+		got, gotEvent = push, e
+		return nil
+	})
+	var other []string
+	Handle(rcv, "", func(ctx context.Context, e Event, raw json.RawMessage) error {
+		other = append(other, e.Type+" "+string(raw))
+		return nil
+	})
+
+	// when
+	push := deliver(rcv, "d-1", "push", `{"ref":"refs/heads/main"}`)
+	star := deliver(rcv, "d-2", "star", `{}`)
+
+	// then
+	assert.Equal(t, http.StatusOK, push.Code)
+	assert.Equal(t, "refs/heads/main", got.Ref)
+	assert.Equal(t, "d-1", gotEvent.ID)
+	assert.Equal(t, http.StatusOK, star.Code)
+	assert.Equal(t, []string{"star {}"}, other)
+}
+
+func TestReceiverDropsReplays(t *testing.T) {
+	// given
+	captureLog(t)
+	rcv := New(GitHub("secret"))
+	now := time.Now()
+	rcv.now = func() time.Time { return now }
+	calls := 0
+	Handle(rcv, "push", func(ctx context.Context, e Event, push pushEvent) error {
+		calls++
+		return nil
+	})
+
+	// when
+	first := deliver(rcv, "d-1", "push", `{"ref":"main"}`)
+	replay := deliver(rcv, "d-1", "push", `{"ref":"main"}`)
+	now = now.Add(25 * time.Hour)
+	late := deliver(rcv, "d-1", "push", `{"ref":"main"}`)
+
+	// then
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusOK, replay.Code)
+	assert.Equal(t, http.StatusOK, late.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestReceiverRetriesFailedDeliveries(t *testing.T) {
+	// given
+	logs := captureLog(t)
+	rcv := New(GitHub("secret"))
+	fail := true
+	calls := 0
+	Handle(rcv, "push", func(ctx context.Context, e Event, push pushEvent) error {
+		calls++
+		if fail {
+			return errors.New("db is down")
+		}
+		return nil
+	})
+
+	// when
+	failed := deliver(rcv, "d-1", "push", `{"ref":"main"}`)
+	fail = false
+	retried := deliver(rcv, "d-1", "push", `{"ref":"main"}`)
+
+	// then
+	assert.Equal(t, http.StatusInternalServerError, failed.Code)
+	assert.Equal(t, http.StatusOK, retried.Code)
+	assert.Equal(t, 2, calls)
+	assert.Contains(t, logs.String(), "db is down")
+}
+
+func TestReceiverRejectsBadRequests(t *testing.T) {
+	// given
+	captureLog(t)
+	rcv := NewWithOptions(GitHub("secret"), Options{MaxBodySize: 64})
+	Handle(rcv, "push", func(ctx context.Context, e Event, push pushEvent) error {
+		return nil
+	})
+
+	// when
+	invalidPayload := deliver(rcv, "d-1", "push", `{"ref":""}`)
+	brokenJSON := deliver(rcv, "d-2", "push", `{"ref":`)
+	tooLarge := deliver(rcv, "d-3", "push", `{"ref":"`+strings.Repeat("a", 100)+`"}`)
+	unsigned := httptest.NewRecorder()
+	rcv.ServeHTTP(unsigned, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"ref":"main"}`)))
+	get := httptest.NewRecorder()
+	rcv.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, invalidPayload.Code)
+	assert.Contains(t, invalidPayload.Body.String(), "Ref is required")
+	assert.Equal(t, http.StatusBadRequest, brokenJSON.Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, tooLarge.Code)
+	assert.Equal(t, http.StatusUnauthorized, unsigned.Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, get.Code)
+}